@@ -0,0 +1,318 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrDispatcherClosed is returned when an event is dispatched to an AsyncDispatcher that has been shut down.
+var ErrDispatcherClosed = errors.New("event: dispatcher is closed")
+
+// defaultQueueSize is the number of events an AsyncDispatcher can hold before DispatchAsync blocks.
+const defaultQueueSize = 256
+
+// AsyncOption configures an AsyncDispatcher.
+type AsyncOption func(*AsyncDispatcher)
+
+// WithWorkers sets the number of worker goroutines used to dispatch events.
+// Values lower than one are ignored.
+func WithWorkers(n int) AsyncOption {
+	return func(a *AsyncDispatcher) {
+		if n > 0 {
+			a.workers = n
+		}
+	}
+}
+
+// WithQueueSize sets the number of events that can be queued before DispatchAsync blocks.
+// Values lower than zero are ignored.
+func WithQueueSize(n int) AsyncOption {
+	return func(a *AsyncDispatcher) {
+		if n >= 0 {
+			a.queueSize = n
+		}
+	}
+}
+
+// Future represents the pending result of an asynchronous dispatch.
+type Future struct {
+	ctx       context.Context
+	event     Event
+	result    *DispatchResult
+	err       error
+	done      chan struct{}
+	mu        sync.Mutex
+	callbacks []func(Event)
+}
+
+func newFuture(ctx context.Context, event Event) *Future {
+	return &Future{
+		ctx:   ctx,
+		event: event,
+		done:  make(chan struct{}),
+	}
+}
+
+// Event returns the event being dispatched.
+func (f *Future) Event() Event {
+	return f.event
+}
+
+// Done returns a channel that is closed once every listener has handled the event.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Err returns the error that stopped the dispatch, if any: the cause of the context's cancellation,
+// the *ListenerError of a listener that timed out under TimeoutStop, or the *ListenerPanicError
+// of a listener that panicked under PanicPropagate.
+// It returns nil until the future is done.
+func (f *Future) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Result returns the outcome of every listener, or nil until the future is done.
+func (f *Future) Result() *DispatchResult {
+	select {
+	case <-f.done:
+		return f.result
+	default:
+		return nil
+	}
+}
+
+// Wait blocks until the event has been dispatched or the context is done.
+// It returns the error that stopped the dispatch, or the context's error if waiting was abandoned.
+func (f *Future) Wait(ctx context.Context) (Event, error) {
+	select {
+	case <-f.done:
+		return f.event, f.err
+	case <-ctx.Done():
+		return f.event, ctx.Err()
+	}
+}
+
+// OnComplete registers a callback that is called once the event has been dispatched.
+// If the dispatch has already finished, the callback is called immediately on the caller's goroutine.
+func (f *Future) OnComplete(callback func(Event)) {
+	f.mu.Lock()
+	select {
+	case <-f.done:
+		f.mu.Unlock()
+		callback(f.event)
+		return
+	default:
+	}
+	f.callbacks = append(f.callbacks, callback)
+	f.mu.Unlock()
+}
+
+// complete marks the future as done and runs its callbacks.
+func (f *Future) complete() {
+	f.mu.Lock()
+	close(f.done)
+	callbacks := f.callbacks
+	f.callbacks = nil
+	f.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(f.event)
+	}
+}
+
+// AsyncDispatcher dispatches events on a bounded pool of worker goroutines.
+//
+// Listeners are registered on the wrapped EventDispatcher, so each event is still handled
+// by its listeners in priority order; only the caller no longer waits for them.
+//
+// Listener panics are never re-raised on the workers. Under PanicPropagate a panic skips the
+// remaining listeners and is returned by Future.Err.
+type AsyncDispatcher struct {
+	dispatcher *EventDispatcher
+	workers    int
+	queueSize  int
+	queue      chan *Future
+	wg         sync.WaitGroup
+
+	// mu guards closed. Producers register in senders under the read lock and then send without
+	// holding it, so that Shutdown never waits for a producer blocked on a full queue.
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{} // closed by Shutdown to release blocked producers
+	senders sync.WaitGroup
+	drained chan struct{} // closed once every queued event has been dispatched
+}
+
+// NewAsyncDispatcher creates an asynchronous dispatcher on top of the given event dispatcher.
+// If dispatcher is nil, a new one is created.
+func NewAsyncDispatcher(dispatcher *EventDispatcher, opts ...AsyncOption) *AsyncDispatcher {
+	if dispatcher == nil {
+		dispatcher = NewDispatcher()
+	}
+
+	a := &AsyncDispatcher{
+		dispatcher: dispatcher,
+		workers:    runtime.GOMAXPROCS(0),
+		queueSize:  defaultQueueSize,
+		closing:    make(chan struct{}),
+		drained:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	a.queue = make(chan *Future, a.queueSize)
+
+	a.wg.Add(a.workers)
+	for i := 0; i < a.workers; i++ {
+		go a.work()
+	}
+
+	return a
+}
+
+// work dispatches queued events until the queue is closed.
+func (a *AsyncDispatcher) work() {
+	defer a.wg.Done()
+
+	for future := range a.queue {
+		retries := &backgroundRetries{event: future.event}
+		ctx := context.WithValue(future.ctx, backgroundRetriesKey{}, retries)
+
+		future.result = a.dispatcher.dispatchWithResult(ctx, future.event)
+
+		if retries.empty() {
+			a.finish(future)
+			continue
+		}
+
+		// Retry in the background so that the worker moves on to the next event
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+
+			retries.run(ctx)
+			a.finish(future)
+		}()
+	}
+}
+
+// finish records the error that stopped the dispatch and completes the future.
+//
+// Under PanicPropagate there is no caller to re-raise a listener panic on, and re-raising it on
+// the worker would crash the program, so the panic is passed to the panic handler and returned
+// by Err instead, as under PanicRecoverStop.
+func (a *AsyncDispatcher) finish(future *Future) {
+	future.err = a.dispatcher.stoppedBy(future.result)
+
+	if panicErr := a.dispatcher.propagated(future.result); panicErr != nil {
+		if a.dispatcher.panicHandler != nil {
+			a.dispatcher.panicHandler(panicErr)
+		}
+		if future.err == nil {
+			future.err = panicErr
+		}
+	}
+
+	future.complete()
+}
+
+// AddListener adds a listener for the specified event.
+func (a *AsyncDispatcher) AddListener(eventName string, listener Listener, priority ...int) {
+	a.dispatcher.AddListener(eventName, listener, priority...)
+}
+
+// Listen adds a listener for the specified event and returns a subscription to remove it.
+func (a *AsyncDispatcher) Listen(eventName string, listener Listener, opts ...ListenerOption) (*Subscription, error) {
+	return a.dispatcher.Listen(eventName, listener, opts...)
+}
+
+// removeOwner removes every listener registered by the given subscriber.
+func (a *AsyncDispatcher) removeOwner(owner interface{}) {
+	a.dispatcher.removeOwner(owner)
+}
+
+// HasListener checks if a listener is registered for the specified event.
+func (a *AsyncDispatcher) HasListener(eventName string, listener Listener) bool {
+	return a.dispatcher.HasListener(eventName, listener)
+}
+
+// RemoveListener removes a listener from the specified event.
+func (a *AsyncDispatcher) RemoveListener(eventName string, listener Listener) {
+	a.dispatcher.RemoveListener(eventName, listener)
+}
+
+// Dispatch queues an event and returns it without waiting for the listeners.
+// Events dispatched after Shutdown are dropped; use DispatchAsync to observe that case.
+func (a *AsyncDispatcher) Dispatch(event Event) Event {
+	_, _ = a.DispatchAsync(event)
+	return event
+}
+
+// DispatchAsync queues an event for dispatch and returns a future for its completion.
+// It blocks while the queue is full and returns ErrDispatcherClosed after Shutdown.
+func (a *AsyncDispatcher) DispatchAsync(event Event) (*Future, error) {
+	return a.DispatchAsyncContext(context.Background(), event)
+}
+
+// DispatchAsyncContext queues an event for dispatch within the given context.
+//
+// The context is passed to the listeners when the event is dispatched. If it is done
+// while waiting for room in the queue, the event is not queued and the context's error is returned.
+// If Shutdown is called while waiting, the event is not queued and ErrDispatcherClosed is returned.
+func (a *AsyncDispatcher) DispatchAsyncContext(ctx context.Context, event Event) (*Future, error) {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return nil, ErrDispatcherClosed
+	}
+	a.senders.Add(1)
+	a.mu.RUnlock()
+	defer a.senders.Done()
+
+	future := newFuture(ctx, event)
+
+	select {
+	case a.queue <- future:
+		return future, nil
+	case <-a.closing:
+		return nil, ErrDispatcherClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Shutdown stops accepting new events and waits until every queued event has been dispatched.
+// If the context is done first, Shutdown returns the context's error while the workers keep draining.
+func (a *AsyncDispatcher) Shutdown(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.closing)
+
+		go func() {
+			// The queue is closed once no producer can send to it anymore
+			a.senders.Wait()
+			close(a.queue)
+
+			a.wg.Wait()
+			close(a.drained)
+		}()
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-a.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,240 @@
+package event_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncDispatcher_DispatchAsync(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(nil, event.WithWorkers(2))
+	defer dispatcher.Shutdown(context.Background())
+
+	listener := &TestListener{}
+	dispatcher.AddListener("user.created", listener)
+
+	e := event.NewEvent("user.created")
+	future, err := dispatcher.DispatchAsync(e)
+	require.NoError(t, err)
+
+	dispatched, err := future.Wait(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, e, dispatched)
+	assert.True(t, listener.called)
+	require.NotNil(t, future.Result())
+	assert.Len(t, future.Result().Listeners, 1)
+}
+
+func TestAsyncDispatcher_PanicIsReturnedByFuture(t *testing.T) {
+	var handled atomic.Int32
+	dispatcher := event.NewAsyncDispatcher(event.NewDispatcher(event.WithPanicHandler(func(err *event.ListenerPanicError) {
+		handled.Add(1)
+	})))
+	defer dispatcher.Shutdown(context.Background())
+
+	dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+		panic("boom")
+	}), 10)
+	listener := &TestListener{}
+	dispatcher.AddListener("user.created", listener)
+
+	future, err := dispatcher.DispatchAsync(event.NewEvent("user.created"))
+	require.NoError(t, err)
+
+	_, err = future.Wait(context.Background())
+
+	var panicErr *event.ListenerPanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Equal(t, event.OutcomePanicked, future.Result().Listeners[0].Outcome)
+	assert.Equal(t, event.OutcomeSkipped, future.Result().Listeners[1].Outcome)
+	assert.False(t, listener.called)
+	assert.Equal(t, int32(1), handled.Load())
+
+	// The workers survive the panic
+	future, err = dispatcher.DispatchAsync(event.NewEvent("user.deleted"))
+	require.NoError(t, err)
+	_, err = future.Wait(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestAsyncDispatcher_PriorityWithinEvent(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(nil, event.WithWorkers(4))
+	defer dispatcher.Shutdown(context.Background())
+
+	var callOrder []int
+
+	dispatcher.AddListener("test.event", event.ListenerFunc(func(e event.Event) bool {
+		callOrder = append(callOrder, 1)
+		return true
+	}), 10)
+
+	dispatcher.AddListener("test.event", event.ListenerFunc(func(e event.Event) bool {
+		callOrder = append(callOrder, 2)
+		return true
+	}), 50)
+
+	future, err := dispatcher.DispatchAsync(event.NewEvent("test.event"))
+	require.NoError(t, err)
+	<-future.Done()
+
+	assert.Equal(t, []int{2, 1}, callOrder)
+}
+
+func TestAsyncDispatcher_OnComplete(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(nil)
+	defer dispatcher.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	dispatcher.AddListener("test.event", event.ListenerFunc(func(e event.Event) bool {
+		<-release
+		return true
+	}))
+
+	future, err := dispatcher.DispatchAsync(event.NewEvent("test.event"))
+	require.NoError(t, err)
+
+	completed := make(chan event.Event, 1)
+	future.OnComplete(func(e event.Event) {
+		completed <- e
+	})
+	close(release)
+
+	select {
+	case e := <-completed:
+		assert.Equal(t, "test.event", e.Name())
+	case <-time.After(time.Second):
+		t.Fatal("callback was not called")
+	}
+
+	// Callbacks registered after completion run immediately
+	called := false
+	future.OnComplete(func(event.Event) {
+		called = true
+	})
+	assert.True(t, called)
+}
+
+func TestAsyncDispatcher_ShutdownDrainsQueue(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(nil, event.WithWorkers(1), event.WithQueueSize(10))
+
+	var handled int32
+	dispatcher.AddListener("test.event", event.ListenerFunc(func(e event.Event) bool {
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&handled, 1)
+		return true
+	}))
+
+	for i := 0; i < 10; i++ {
+		dispatcher.Dispatch(event.NewEvent("test.event"))
+	}
+
+	require.NoError(t, dispatcher.Shutdown(context.Background()))
+	assert.Equal(t, int32(10), atomic.LoadInt32(&handled))
+
+	_, err := dispatcher.DispatchAsync(event.NewEvent("test.event"))
+	assert.ErrorIs(t, err, event.ErrDispatcherClosed)
+}
+
+func TestAsyncDispatcher_ShutdownDeadline(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(nil, event.WithWorkers(1))
+
+	release := make(chan struct{})
+	var once sync.Once
+	dispatcher.AddListener("test.event", event.ListenerFunc(func(e event.Event) bool {
+		<-release
+		return true
+	}))
+	defer once.Do(func() { close(release) })
+
+	dispatcher.Dispatch(event.NewEvent("test.event"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, dispatcher.Shutdown(ctx), context.DeadlineExceeded)
+
+	once.Do(func() { close(release) })
+	assert.NoError(t, dispatcher.Shutdown(context.Background()))
+}
+
+func TestAsyncDispatcher_ShutdownReleasesBlockedProducers(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(nil, event.WithWorkers(1), event.WithQueueSize(0))
+
+	release := make(chan struct{})
+	dispatcher.AddListener("test.event", event.ListenerFunc(func(e event.Event) bool {
+		<-release
+		return true
+	}))
+
+	// The only worker is stuck, so the next producer blocks on the full queue
+	_, err := dispatcher.DispatchAsync(event.NewEvent("test.event"))
+	require.NoError(t, err)
+
+	blocked := make(chan error, 1)
+	go func() {
+		_, err := dispatcher.DispatchAsync(event.NewEvent("test.event"))
+		blocked <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- dispatcher.Shutdown(ctx)
+	}()
+
+	select {
+	case err := <-shutdown:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("shutdown did not return at its deadline")
+	}
+	assert.ErrorIs(t, <-blocked, event.ErrDispatcherClosed)
+
+	close(release)
+	assert.NoError(t, dispatcher.Shutdown(context.Background()))
+}
+
+func TestAsyncDispatcher_ImplementsDispatcher(t *testing.T) {
+	var dispatcher event.Dispatcher = event.NewAsyncDispatcher(nil)
+	listener := &TestListener{}
+
+	dispatcher.AddListener("user.created", listener)
+	assert.True(t, dispatcher.HasListener("user.created", listener))
+
+	dispatcher.RemoveListener("user.created", listener)
+	assert.False(t, dispatcher.HasListener("user.created", listener))
+}
+
+func TestAsyncDispatcher_DispatchAsyncContext(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(nil)
+	defer dispatcher.Shutdown(context.Background())
+
+	listener := &TestListener{}
+	dispatcher.AddListener("user.created", listener)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	future, err := dispatcher.DispatchAsyncContext(ctx, event.NewEvent("user.created"))
+	if err != nil {
+		// The canceled context won the race for the queue
+		assert.ErrorIs(t, err, context.Canceled)
+		return
+	}
+
+	_, err = future.Wait(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, future.Err(), context.Canceled)
+	assert.False(t, listener.called)
+}
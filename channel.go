@@ -0,0 +1,243 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrChannelFull is reported by channel subscriptions with the OverflowError policy
+// for every event dropped because the channel was full.
+var ErrChannelFull = errors.New("event: subscription channel is full")
+
+// OverflowPolicy controls what a channel subscription does with an event when its channel is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the channel has room, the subscription is cancelled or the context
+	// of the dispatch is done. This is the default.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest discards the oldest buffered event to make room for the new one.
+	OverflowDropOldest
+
+	// OverflowDropNewest discards the new event.
+	OverflowDropNewest
+
+	// OverflowError discards the new event and reports ErrChannelFull as the listener's error,
+	// so that the drop shows up in DispatchWithResult and the errors of Emit.
+	OverflowError
+)
+
+// channelOptions holds the settings applied by ChannelOption values.
+type channelOptions struct {
+	overflow OverflowPolicy
+	dropped  *atomic.Uint64
+	listener []ListenerOption
+}
+
+// ChannelOption configures a channel subscription created with Subscribe, SubscribeErr or Events.
+type ChannelOption func(*channelOptions)
+
+// WithOverflow sets what the subscription does with an event when its channel is full.
+func WithOverflow(policy OverflowPolicy) ChannelOption {
+	return func(o *channelOptions) {
+		o.overflow = policy
+	}
+}
+
+// WithDropCounter counts the events dropped by the subscription in counter.
+func WithDropCounter(counter *atomic.Uint64) ChannelOption {
+	return func(o *channelOptions) {
+		o.dropped = counter
+	}
+}
+
+// WithListenerOptions applies listener options, such as WithPriority or Where,
+// to the listener that feeds the channel.
+func WithListenerOptions(opts ...ListenerOption) ChannelOption {
+	return func(o *channelOptions) {
+		o.listener = append(o.listener, opts...)
+	}
+}
+
+// channelListener is the listener that sends events to the channel of a subscription.
+type channelListener struct {
+	events   chan Event
+	done     chan struct{}
+	overflow OverflowPolicy
+	dropped  *atomic.Uint64
+
+	// mu is held for reading while sending, so that cancel closes events only once no send is in flight.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Handle implements the Listener interface for channelListener.
+func (c *channelListener) Handle(e Event) bool {
+	return c.HandleEvent(context.Background(), e) == nil
+}
+
+// HandleEvent implements the ErrorListener interface for channelListener.
+func (c *channelListener) HandleEvent(ctx context.Context, e Event) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil
+	}
+
+	switch c.overflow {
+	case OverflowDropOldest:
+		for {
+			select {
+			case c.events <- e:
+				return nil
+			default:
+			}
+
+			select {
+			case <-c.events:
+				c.drop()
+			default:
+				// An unbuffered channel holds no older event to discard
+				if cap(c.events) == 0 {
+					c.drop()
+					return nil
+				}
+			}
+		}
+
+	case OverflowDropNewest, OverflowError:
+		select {
+		case c.events <- e:
+			return nil
+		default:
+		}
+
+		c.drop()
+		if c.overflow == OverflowError {
+			return ErrChannelFull
+		}
+		return nil
+
+	default:
+		select {
+		case c.events <- e:
+			return nil
+		case <-c.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// drop counts a dropped event.
+func (c *channelListener) drop() {
+	if c.dropped != nil {
+		c.dropped.Add(1)
+	}
+}
+
+// cancel stops sending events and closes the channel once no send is in flight.
+func (c *channelListener) cancel() {
+	close(c.done)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	close(c.events)
+}
+
+// Subscribe returns a channel receiving the events dispatched under the event name or pattern,
+// for consumers that are goroutine loops rather than callbacks. The channel buffers up to
+// bufSize events; what happens when it is full is set with WithOverflow.
+//
+// The returned cancel function detaches the listener and then closes the channel.
+// Calling it more than once has no further effect. If the listener options are rejected,
+// for example because of an ordering cycle, the channel is returned closed; use SubscribeErr
+// to learn why.
+//
+//	events, cancel := event.Subscribe(dispatcher, "order.*", 64, event.WithOverflow(event.OverflowDropOldest))
+//	defer cancel()
+//
+//	for e := range events {
+//		process(e)
+//	}
+func Subscribe(d *EventDispatcher, pattern string, bufSize int, opts ...ChannelOption) (<-chan Event, func()) {
+	events, cancel, err := SubscribeErr(d, pattern, bufSize, opts...)
+	if err != nil {
+		closed := make(chan Event)
+		close(closed)
+		return closed, func() {}
+	}
+
+	return events, cancel
+}
+
+// SubscribeErr is like Subscribe but returns the error of a subscription whose listener options
+// are rejected, such as an *OrderCycleError, instead of a closed channel.
+func SubscribeErr(d *EventDispatcher, pattern string, bufSize int, opts ...ChannelOption) (<-chan Event, func(), error) {
+	var options channelOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	listener := &channelListener{
+		events:   make(chan Event, max(bufSize, 0)),
+		done:     make(chan struct{}),
+		overflow: options.overflow,
+		dropped:  options.dropped,
+	}
+
+	sub, err := d.Listen(pattern, listener, options.listener...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var once sync.Once
+	return listener.events, func() {
+		once.Do(func() {
+			sub.Unsubscribe()
+			listener.cancel()
+		})
+	}, nil
+}
+
+// Events returns an iterator over the events dispatched under the event name or pattern.
+//
+// The subscription is created when the iteration starts, so events dispatched before are not seen,
+// and is cancelled when the loop ends or the context is done. If the subscription is rejected, as
+// described for SubscribeErr, the iterator yields its error once, with a nil event, and ends.
+//
+//	for e, err := range event.Events(ctx, dispatcher, "order.*", 64) {
+//		if err != nil {
+//			return err
+//		}
+//		process(e)
+//	}
+func Events(ctx context.Context, d *EventDispatcher, pattern string, bufSize int, opts ...ChannelOption) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		events, cancel, err := SubscribeErr(d, pattern, bufSize, opts...)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer cancel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-events:
+				if !ok || !yield(e, nil) {
+					return
+				}
+			}
+		}
+	}
+}
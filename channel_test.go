@@ -0,0 +1,215 @@
+package event_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receive drains the events buffered in the channel and returns their names.
+func receive(events <-chan event.Event) []string {
+	var names []string
+	for {
+		select {
+		case e := <-events:
+			names = append(names, e.Name())
+		default:
+			return names
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	events, cancel := event.Subscribe(dispatcher, "order.*", 4)
+	defer cancel()
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	dispatcher.Dispatch(event.NewEvent("order.shipped"))
+
+	assert.Equal(t, []string{"order.created", "order.shipped"}, receive(events))
+}
+
+func TestSubscribe_Cancel(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	events, cancel := event.Subscribe(dispatcher, "order.created", 4)
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+	cancel()
+	cancel()
+
+	assert.Empty(t, dispatcher.Listeners("order.created"))
+
+	// Buffered events are still received before the channel reports closed
+	e, ok := <-events
+	require.True(t, ok)
+	assert.Equal(t, "order.created", e.Name())
+
+	_, ok = <-events
+	assert.False(t, ok)
+
+	// Dispatching after cancel must not send on the closed channel
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+}
+
+func TestSubscribe_OverflowBlock(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	events, cancel := event.Subscribe(dispatcher, "tick", 1)
+	defer cancel()
+
+	dispatcher.Dispatch(event.NewEvent("tick"))
+
+	ctx, stop := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer stop()
+
+	result := dispatcher.DispatchWithResult(ctx, event.NewEvent("tick"))
+	assert.ErrorIs(t, result.Err(), context.DeadlineExceeded)
+	assert.Len(t, receive(events), 1)
+}
+
+func TestSubscribe_OverflowBlockUnblockedByCancel(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	_, cancel := event.Subscribe(dispatcher, "tick", 0)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatcher.Dispatch(event.NewEvent("tick"))
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatch still blocked after cancel")
+	}
+}
+
+func TestSubscribe_OverflowDrop(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   event.OverflowPolicy
+		expected []string
+		failed   int
+	}{
+		{"drop oldest", event.OverflowDropOldest, []string{"third", "fourth"}, 0},
+		{"drop newest", event.OverflowDropNewest, []string{"first", "second"}, 0},
+		{"error", event.OverflowError, []string{"first", "second"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := event.NewDispatcher()
+
+			var dropped atomic.Uint64
+			events, cancel := event.Subscribe(dispatcher, "*", 2,
+				event.WithOverflow(tt.policy),
+				event.WithDropCounter(&dropped),
+			)
+			defer cancel()
+
+			failed := 0
+			for _, name := range []string{"first", "second", "third", "fourth"} {
+				result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent(name))
+				for _, l := range result.Failed() {
+					assert.ErrorIs(t, l.Err, event.ErrChannelFull)
+					failed++
+				}
+			}
+
+			assert.Equal(t, tt.expected, receive(events))
+			assert.Equal(t, uint64(2), dropped.Load())
+			assert.Equal(t, tt.failed, failed)
+		})
+	}
+}
+
+func TestSubscribe_ListenerOptions(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	events, cancel := event.Subscribe(dispatcher, "order.created", 4,
+		event.WithListenerOptions(event.Where(event.ArgEquals("tenant", "acme"))),
+	)
+	defer cancel()
+
+	dispatcher.Dispatch(event.NewEvent("order.created", map[string]interface{}{"tenant": "acme"}))
+	dispatcher.Dispatch(event.NewEvent("order.created", map[string]interface{}{"tenant": "other"}))
+
+	assert.Len(t, receive(events), 1)
+}
+
+func TestSubscribeErr_RejectedOptions(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	_, err := dispatcher.Listen("order.created", event.ListenerFunc(func(e event.Event) bool {
+		return true
+	}), event.WithName("audit"), event.Before("consumer"))
+	require.NoError(t, err)
+
+	cycle := event.WithListenerOptions(event.WithName("consumer"), event.Before("audit"))
+
+	events, cancel, err := event.SubscribeErr(dispatcher, "order.created", 4, cycle)
+	assert.ErrorIs(t, err, event.ErrOrderCycle)
+	assert.Nil(t, events)
+	assert.Nil(t, cancel)
+
+	var yielded []error
+	for e, err := range event.Events(context.Background(), dispatcher, "order.created", 4, cycle) {
+		assert.Nil(t, e)
+		yielded = append(yielded, err)
+	}
+	require.Len(t, yielded, 1)
+	assert.ErrorIs(t, yielded[0], event.ErrOrderCycle)
+
+	// Subscribe keeps returning a closed channel
+	closed, cancel := event.Subscribe(dispatcher, "order.created", 4, cycle)
+	defer cancel()
+	_, ok := <-closed
+	assert.False(t, ok)
+	assert.Len(t, dispatcher.Listeners("order.created"), 1)
+}
+
+func TestEvents(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	go func() {
+		// Start dispatching once the iterator has subscribed
+		for len(dispatcher.Listeners("order.created")) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		for _, name := range []string{"order.created", "order.paid", "order.shipped"} {
+			dispatcher.Dispatch(event.NewEvent(name))
+		}
+	}()
+
+	var names []string
+	for e, err := range event.Events(context.Background(), dispatcher, "order.*", 4) {
+		require.NoError(t, err)
+		names = append(names, e.Name())
+		if len(names) == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"order.created", "order.paid"}, names)
+	assert.Empty(t, dispatcher.Listeners("order.shipped"))
+}
+
+func TestEvents_ContextDone(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	for range event.Events(ctx, dispatcher, "order.*", 4) {
+		t.Fatal("no event was dispatched")
+	}
+
+	assert.Empty(t, dispatcher.Listeners("order.created"))
+}
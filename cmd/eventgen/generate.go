@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// outputTemplate renders the generated file. Its data is a templateData.
+var outputTemplate = template.Must(template.New("output").Parse(`// Code generated by eventgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range $i, $group := .Imports}}
+{{- if $i}}
+{{end}}
+{{- range $group}}
+	{{.}}
+{{- end}}
+{{- end}}
+)
+{{- if .Events}}
+
+// Event names.
+const (
+{{- range .Events}}
+	// {{.Const}} is the name of {{.TypeName}} events.
+	{{.Const}} = {{printf "%q" .EventName}}
+{{- end}}
+)
+{{- end}}
+{{- range .Events}}
+{{- if .Constructor}}
+
+// {{.Constructor}} creates a new {{.TypeName}}.
+func {{.Constructor}}({{range $i, $f := .Fields}}{{if $i}}, {{end}}{{$f.Param}} {{$f.Type}}{{end}}) *{{.TypeName}} {
+	return &{{.TypeName}}{
+		BaseEvent: {{$.EventPkg}}.NewEvent({{.Const}}),
+{{- range .Fields}}
+		{{.Name}}: {{.Param}},
+{{- end}}
+	}
+}
+{{- end}}
+{{- end}}
+{{- range .Subscribers}}
+
+// GetSubscribedEvents implements the {{$.EventPkg}}.Subscriber interface for {{.TypeName}}.
+func (s {{.Receiver}}) GetSubscribedEvents() map[string][]{{$.EventPkg}}.SubscriberConfig {
+	return map[string][]{{$.EventPkg}}.SubscriberConfig{
+{{- range .Groups}}
+		{{.Key}}: {
+{{- range .Listeners}}
+			{Method: {{printf "%q" .Method}}, Priority: {{.Priority}}},
+{{- end}}
+		},
+{{- end}}
+	}
+}
+
+// BindListeners implements the {{$.EventPkg}}.BoundSubscriber interface for {{.TypeName}}.
+func (s {{.Receiver}}) BindListeners() map[string][]{{$.EventPkg}}.BoundListener {
+	return map[string][]{{$.EventPkg}}.BoundListener{
+{{- range .Groups}}
+		{{.Key}}: {
+{{- range .Listeners}}
+			{
+				Config: {{$.EventPkg}}.SubscriberConfig{Method: {{printf "%q" .Method}}, Priority: {{.Priority}}},
+				Listener: {{$.EventPkg}}.TypedContextListener(func(ctx context.Context, e {{.EventType}}) error {
+					{{.Call}}
+				}),
+			},
+{{- end}}
+		},
+{{- end}}
+	}
+}
+{{- end}}
+`))
+
+// templateData is the data rendered by outputTemplate.
+type templateData struct {
+	Package     string
+	EventPkg    string
+	Imports     [][]string
+	Events      []eventData
+	Subscribers []subscriberData
+}
+
+// eventData describes a generated event constant and constructor.
+type eventData struct {
+	TypeName    string
+	EventName   string
+	Const       string
+	Constructor string
+	Fields      []fieldData
+}
+
+// fieldData is a constructor parameter.
+type fieldData struct {
+	Name  string
+	Param string
+	Type  string
+}
+
+// subscriberData describes the generated methods of a subscriber.
+type subscriberData struct {
+	TypeName string
+	Receiver string
+	Groups   []groupData
+}
+
+// groupData holds the listeners of a subscriber for one event name.
+type groupData struct {
+	Key       string
+	Listeners []listenerData
+}
+
+// listenerData describes a bound subscriber method.
+type listenerData struct {
+	Method    string
+	Priority  int
+	EventType string
+	Call      string
+}
+
+// generate renders the generated file for the package.
+func generate(pkg *packageInfo) ([]byte, error) {
+	if len(pkg.events) == 0 && len(pkg.subscribers) == 0 {
+		return nil, fmt.Errorf("no %s or %s annotations found in package %s", nameDirective, listenDirective, pkg.name)
+	}
+
+	eventPkg := "event"
+	if name, ok := pkg.imports[eventImportPath]; ok {
+		eventPkg = name
+	}
+
+	data := templateData{
+		Package:  pkg.name,
+		EventPkg: eventPkg,
+	}
+
+	// Refer to the constants of the events declared in this package
+	consts := make(map[string]string)
+	for _, e := range pkg.events {
+		constName := "Event" + strings.TrimSuffix(e.typeName, "Event")
+		if prev, ok := consts[e.eventName]; ok {
+			return nil, fmt.Errorf("event name %q is declared by both %s and %s", e.eventName, prev, constName)
+		}
+		consts[e.eventName] = constName
+
+		ed := eventData{
+			TypeName:  e.typeName,
+			EventName: e.eventName,
+			Const:     constName,
+		}
+
+		if constructor := "New" + e.typeName; !pkg.funcs[constructor] {
+			ed.Constructor = constructor
+			for _, f := range e.fields {
+				ed.Fields = append(ed.Fields, fieldData{Name: f.name, Param: paramName(f.name), Type: f.typeExpr})
+			}
+		}
+
+		data.Events = append(data.Events, ed)
+	}
+
+	for _, sub := range pkg.subscribers {
+		sd := subscriberData{TypeName: sub.typeName, Receiver: sub.typeName}
+		if sub.pointer {
+			sd.Receiver = "*" + sub.typeName
+		}
+
+		groups := make(map[string]int)
+		for _, l := range sub.listeners {
+			key, ok := consts[l.eventName]
+			if !ok {
+				key = strconv.Quote(l.eventName)
+			}
+
+			i, ok := groups[key]
+			if !ok {
+				i = len(sd.Groups)
+				groups[key] = i
+				sd.Groups = append(sd.Groups, groupData{Key: key})
+			}
+
+			sd.Groups[i].Listeners = append(sd.Groups[i].Listeners, listenerData{
+				Method:    l.method,
+				Priority:  l.priority,
+				EventType: l.eventType,
+				Call:      callStatement(l, eventPkg),
+			})
+		}
+
+		data.Subscribers = append(data.Subscribers, sd)
+	}
+
+	data.Imports = imports(pkg, eventPkg, len(data.Subscribers) > 0)
+
+	var buf bytes.Buffer
+	if err := outputTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, buf.String())
+	}
+
+	return src, nil
+}
+
+// callStatement returns the statements calling the subscriber method and converting its result to an error.
+func callStatement(l listenerInfo, eventPkg string) string {
+	call := "s." + l.method + "(e)"
+	if l.withContext {
+		call = "s." + l.method + "(ctx, e)"
+	}
+
+	switch l.result {
+	case "error":
+		return "return " + call
+	case "bool":
+		return "if !" + call + " {\n\treturn " + eventPkg + ".ErrListenerFailed\n}\nreturn nil"
+	default:
+		return call + "\nreturn nil"
+	}
+}
+
+// imports returns the sorted import specs of the generated file,
+// grouped into standard library and other packages.
+func imports(pkg *packageInfo, eventPkg string, withContext bool) [][]string {
+	paths := map[string]string{eventImportPath: eventPkg}
+	if withContext {
+		paths["context"] = "context"
+	}
+	for path, name := range pkg.imports {
+		paths[path] = name
+	}
+
+	var std, other []string
+	for path, name := range paths {
+		spec := strconv.Quote(path)
+		if name != filepath.Base(path) {
+			spec = name + " " + spec
+		}
+
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+
+	var groups [][]string
+	for _, group := range [][]string{std, other} {
+		if len(group) > 0 {
+			sort.Strings(group)
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// paramName turns an exported field name into a parameter name, such as OrderID into orderID.
+func paramName(field string) string {
+	runes := []rune(field)
+
+	// Lower the leading run of capitals, keeping the last one when it starts a new word
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	if i > 1 && i < len(runes) {
+		i--
+	}
+	for j := 0; j < i; j++ {
+		runes[j] = unicode.ToLower(runes[j])
+	}
+
+	name := string(runes)
+	if token.IsKeyword(name) {
+		name += "Value"
+	}
+
+	return name
+}
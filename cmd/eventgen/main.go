@@ -0,0 +1,60 @@
+// Command eventgen generates event name constants, typed event constructors and
+// reflection-free subscriber registration from annotated Go source.
+//
+// Annotate event structs embedding *event.BaseEvent with their name:
+//
+//	//event:name order.created
+//	type OrderCreatedEvent struct {
+//		*event.BaseEvent
+//		OrderID string
+//	}
+//
+// and subscriber methods with the events they listen to:
+//
+//	//event:listen order.created priority=10
+//	func (s *NotificationSubscriber) OnOrderCreated(ctx context.Context, e *OrderCreatedEvent) error
+//
+// Then run it from the package directory, typically through go generate:
+//
+//	//go:generate go run github.com/parsilver/event/cmd/eventgen
+//
+// For each event it emits a name constant such as EventOrderCreated and, unless one exists,
+// a constructor such as NewOrderCreatedEvent taking the exported fields. For each subscriber
+// it emits GetSubscribedEvents and BindListeners, so event.RegisterSubscriber binds the
+// methods without reflection.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package to process")
+	output := flag.String("output", "event_gen.go", "name of the generated file, relative to -dir")
+	flag.Parse()
+
+	if err := run(*dir, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "eventgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the output file for the package in dir.
+func run(dir, output string) error {
+	outputPath := filepath.Join(dir, output)
+
+	pkg, err := parsePackage(dir, filepath.Base(outputPath))
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(outputPath, src, 0o600)
+}
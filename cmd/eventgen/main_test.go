@@ -0,0 +1,204 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const source = `package shop
+
+import (
+	"context"
+	"time"
+
+	"github.com/parsilver/event"
+)
+
+//event:name order.created
+type OrderCreatedEvent struct {
+	*event.BaseEvent
+	OrderID  string
+	PlacedAt time.Time
+	internal int
+}
+
+// UserDeletedEvent has a hand-written constructor.
+//
+//event:name user.deleted
+type UserDeletedEvent struct {
+	*event.BaseEvent
+	ID int
+}
+
+func NewUserDeletedEvent(id int) *UserDeletedEvent {
+	return &UserDeletedEvent{BaseEvent: event.NewEvent("user.deleted"), ID: id}
+}
+
+type Mailer struct{}
+
+//event:listen order.created priority=10
+func (m *Mailer) OnOrderCreated(ctx context.Context, e *OrderCreatedEvent) error {
+	return nil
+}
+
+//event:listen user.deleted
+//event:listen user.*
+func (m *Mailer) OnUserEvent(e event.Event) bool {
+	return true
+}
+
+//event:listen order.created priority=-5
+func (m *Mailer) Audit(e *OrderCreatedEvent) {}
+`
+
+func writeSource(t *testing.T, src string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shop.go"), []byte(src), 0o600))
+
+	return dir
+}
+
+func TestRun_GeneratesCode(t *testing.T) {
+	dir := writeSource(t, source)
+
+	require.NoError(t, run(dir, "event_gen.go"))
+
+	out, err := os.ReadFile(filepath.Join(dir, "event_gen.go"))
+	require.NoError(t, err)
+	generated := string(out)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "event_gen.go", out, 0)
+	require.NoError(t, err)
+
+	assert.Contains(t, generated, "// Code generated by eventgen. DO NOT EDIT.")
+	assert.Contains(t, generated, `EventOrderCreated = "order.created"`)
+	assert.Contains(t, generated, `EventUserDeleted = "user.deleted"`)
+	assert.Contains(t, generated, "func NewOrderCreatedEvent(orderID string, placedAt time.Time) *OrderCreatedEvent {")
+	assert.NotContains(t, generated, "func NewUserDeletedEvent")
+	assert.NotContains(t, generated, "internal:")
+	assert.Contains(t, generated, `"time"`)
+
+	assert.Contains(t, generated, "func (s *Mailer) GetSubscribedEvents() map[string][]event.SubscriberConfig {")
+	assert.Contains(t, generated, "func (s *Mailer) BindListeners() map[string][]event.BoundListener {")
+	assert.Contains(t, generated, `{Method: "OnOrderCreated", Priority: 10},`)
+	assert.Contains(t, generated, `{Method: "Audit", Priority: -5},`)
+	assert.Contains(t, generated, `"user.*": {`)
+	assert.Contains(t, generated, "return s.OnOrderCreated(ctx, e)")
+	assert.Contains(t, generated, "if !s.OnUserEvent(e) {")
+	assert.Contains(t, generated, "s.Audit(e)\n")
+}
+
+func TestRun_IsDeterministic(t *testing.T) {
+	dir := writeSource(t, source)
+
+	require.NoError(t, run(dir, "event_gen.go"))
+	first, err := os.ReadFile(filepath.Join(dir, "event_gen.go"))
+	require.NoError(t, err)
+
+	// The previous output is ignored when generating again
+	require.NoError(t, run(dir, "event_gen.go"))
+	second, err := os.ReadFile(filepath.Join(dir, "event_gen.go"))
+	require.NoError(t, err)
+
+	assert.Equal(t, string(first), string(second))
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing BaseEvent": `package shop
+
+//event:name order.created
+type OrderCreatedEvent struct {
+	OrderID string
+}
+`,
+		"bad signature": `package shop
+
+type Mailer struct{}
+
+//event:listen order.created
+func (m *Mailer) OnOrderCreated(a, b, c int) {}
+`,
+		"bad priority": `package shop
+
+import "github.com/parsilver/event"
+
+type Mailer struct{}
+
+//event:listen order.created priority=high
+func (m *Mailer) OnOrderCreated(e event.Event) {}
+`,
+		"no annotations": `package shop
+`,
+		"unresolved import": `package shop
+
+import (
+	"example.com/missing/v2"
+	"github.com/parsilver/event"
+)
+
+//event:name order.created
+type OrderCreatedEvent struct {
+	*event.BaseEvent
+	Total missing.Amount
+}
+`,
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, run(writeSource(t, src), "event_gen.go"))
+		})
+	}
+}
+
+func TestParamName(t *testing.T) {
+	assert.Equal(t, "orderID", paramName("OrderID"))
+	assert.Equal(t, "id", paramName("ID"))
+	assert.Equal(t, "httpServer", paramName("HTTPServer"))
+	assert.Equal(t, "amount", paramName("Amount"))
+	assert.Equal(t, "typeValue", paramName("Type"))
+}
+
+func TestRun_ResolvesPackageNames(t *testing.T) {
+	module := t.TempDir()
+	files := map[string]string{
+		"go.mod":            "module example.com/shop\n\ngo 1.24\n",
+		"money/v2/money.go": "package money\n\ntype Amount int64\n",
+		"orders/orders.go": `package orders
+
+import (
+	"example.com/shop/money/v2"
+	"github.com/parsilver/event"
+)
+
+//event:name order.paid
+type OrderPaidEvent struct {
+	*event.BaseEvent
+	Total money.Amount
+}
+`,
+	}
+	for name, src := range files {
+		path := filepath.Join(module, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o600))
+	}
+
+	dir := filepath.Join(module, "orders")
+	require.NoError(t, run(dir, "event_gen.go"))
+
+	out, err := os.ReadFile(filepath.Join(dir, "event_gen.go"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(out), `money "example.com/shop/money/v2"`)
+	assert.Contains(t, string(out), "func NewOrderPaidEvent(total money.Amount) *OrderPaidEvent {")
+}
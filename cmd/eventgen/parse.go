@@ -0,0 +1,476 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// eventImportPath is the import path of the event package.
+	eventImportPath = "github.com/parsilver/event"
+
+	// nameDirective annotates an event struct with its event name.
+	nameDirective = "//event:name"
+
+	// listenDirective annotates a subscriber method with an event it listens to.
+	listenDirective = "//event:listen"
+)
+
+// packageInfo is the annotated content of a package.
+type packageInfo struct {
+	name        string
+	dir         string
+	events      []*eventInfo
+	subscribers []*subscriberInfo
+
+	// imports maps the import paths used by event field types to their names.
+	imports map[string]string
+
+	// packageNames caches the names of imported packages by import path, or "" if unresolved.
+	packageNames map[string]string
+
+	// funcs holds the names of the package-level functions, to avoid generating existing constructors.
+	funcs map[string]bool
+}
+
+// eventInfo is an annotated event struct.
+type eventInfo struct {
+	typeName  string
+	eventName string
+	fields    []fieldInfo
+}
+
+// fieldInfo is an exported field of an event struct.
+type fieldInfo struct {
+	name     string
+	typeExpr string
+}
+
+// subscriberInfo is a type with annotated listener methods.
+type subscriberInfo struct {
+	typeName  string
+	pointer   bool
+	listeners []listenerInfo
+}
+
+// listenerInfo is an annotated subscriber method.
+type listenerInfo struct {
+	method      string
+	eventName   string
+	priority    int
+	withContext bool
+	eventType   string
+	result      string
+}
+
+// parsePackage parses the non-test Go files in dir, skipping the generated output file.
+func parsePackage(dir, output string) (*packageInfo, error) {
+	fset := token.NewFileSet()
+	filter := func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != output
+	}
+
+	pkgs, err := parser.ParseDir(fset, dir, filter, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	pkg := &packageInfo{
+		dir:          dir,
+		imports:      make(map[string]string),
+		packageNames: make(map[string]string),
+		funcs:        make(map[string]bool),
+	}
+	subscribers := make(map[string]*subscriberInfo)
+
+	for name, astPkg := range pkgs {
+		pkg.name = name
+
+		// Walk the files in a stable order so the output is deterministic
+		fileNames := make([]string, 0, len(astPkg.Files))
+		for fileName := range astPkg.Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+
+		for _, fileName := range fileNames {
+			p := &fileParser{fset: fset, file: astPkg.Files[fileName], pkg: pkg, subscribers: subscribers}
+			if err := p.parse(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return pkg, nil
+}
+
+// fileParser collects the annotations of a single file.
+type fileParser struct {
+	fset        *token.FileSet
+	file        *ast.File
+	pkg         *packageInfo
+	subscribers map[string]*subscriberInfo
+}
+
+// parse collects the annotated declarations of the file.
+func (p *fileParser) parse() error {
+	for _, decl := range p.file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if err := p.parseGenDecl(decl); err != nil {
+				return err
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				p.pkg.funcs[decl.Name.Name] = true
+				continue
+			}
+			if err := p.parseMethod(decl); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseGenDecl collects the annotated event structs of a type declaration.
+func (p *fileParser) parseGenDecl(decl *ast.GenDecl) error {
+	if decl.Tok != token.TYPE {
+		return nil
+	}
+
+	for _, spec := range decl.Specs {
+		typeSpec := spec.(*ast.TypeSpec)
+
+		// A lone type declaration keeps its comment on the GenDecl
+		doc := typeSpec.Doc
+		if doc == nil && len(decl.Specs) == 1 {
+			doc = decl.Doc
+		}
+
+		args, ok := directive(doc, nameDirective)
+		if !ok {
+			continue
+		}
+
+		if len(args) != 1 {
+			return p.errorf(typeSpec.Pos(), "%s expects exactly one event name", nameDirective)
+		}
+
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return p.errorf(typeSpec.Pos(), "%s must annotate a struct type", nameDirective)
+		}
+
+		event, err := p.parseEvent(typeSpec.Name.Name, args[0], structType)
+		if err != nil {
+			return err
+		}
+
+		p.pkg.events = append(p.pkg.events, event)
+	}
+
+	return nil
+}
+
+// parseEvent collects the exported fields of an event struct and checks that it embeds *event.BaseEvent.
+func (p *fileParser) parseEvent(typeName, eventName string, structType *ast.StructType) (*eventInfo, error) {
+	event := &eventInfo{typeName: typeName, eventName: eventName}
+	embedsBase := false
+
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			if p.isBaseEvent(field.Type) {
+				embedsBase = true
+			}
+			continue
+		}
+
+		typeExpr, err := p.typeString(field.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range field.Names {
+			if name.IsExported() {
+				event.fields = append(event.fields, fieldInfo{name: name.Name, typeExpr: typeExpr})
+			}
+		}
+	}
+
+	if !embedsBase {
+		return nil, p.errorf(structType.Pos(), "%s must embed *event.BaseEvent", typeName)
+	}
+
+	return event, nil
+}
+
+// isBaseEvent reports whether the expression is *event.BaseEvent.
+func (p *fileParser) isBaseEvent(expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "BaseEvent" {
+		return false
+	}
+
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && p.importPath(ident.Name) == eventImportPath
+}
+
+// parseMethod collects the listeners of an annotated subscriber method.
+func (p *fileParser) parseMethod(decl *ast.FuncDecl) error {
+	var listens [][]string
+	if decl.Doc != nil {
+		for _, comment := range decl.Doc.List {
+			if args, ok := parseDirective(comment.Text, listenDirective); ok {
+				listens = append(listens, args)
+			}
+		}
+	}
+
+	if len(listens) == 0 {
+		return nil
+	}
+
+	typeName, pointer := receiverType(decl.Recv.List[0].Type)
+	if typeName == "" {
+		return p.errorf(decl.Pos(), "cannot determine the receiver type of %s", decl.Name.Name)
+	}
+
+	signature, err := p.parseSignature(decl)
+	if err != nil {
+		return err
+	}
+
+	sub, ok := p.subscribers[typeName]
+	if !ok {
+		sub = &subscriberInfo{typeName: typeName}
+		p.subscribers[typeName] = sub
+		p.pkg.subscribers = append(p.pkg.subscribers, sub)
+	}
+	sub.pointer = sub.pointer || pointer
+
+	for _, args := range listens {
+		listener := signature
+		if len(args) == 0 {
+			return p.errorf(decl.Pos(), "%s on %s expects an event name", listenDirective, decl.Name.Name)
+		}
+		listener.eventName = args[0]
+
+		for _, arg := range args[1:] {
+			key, value, found := strings.Cut(arg, "=")
+			if !found || key != "priority" {
+				return p.errorf(decl.Pos(), "unknown %s option %q on %s", listenDirective, arg, decl.Name.Name)
+			}
+
+			priority, err := strconv.Atoi(value)
+			if err != nil {
+				return p.errorf(decl.Pos(), "invalid priority %q on %s", value, decl.Name.Name)
+			}
+			listener.priority = priority
+		}
+
+		sub.listeners = append(sub.listeners, listener)
+	}
+
+	return nil
+}
+
+// parseSignature checks that the method takes the event, optionally preceded by a context.Context,
+// and returns nothing, a bool or an error.
+func (p *fileParser) parseSignature(decl *ast.FuncDecl) (listenerInfo, error) {
+	listener := listenerInfo{method: decl.Name.Name}
+
+	var params []ast.Expr
+	for _, field := range decl.Type.Params.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			params = append(params, field.Type)
+		}
+	}
+
+	switch len(params) {
+	case 1:
+	case 2:
+		sel, ok := params[0].(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Context" {
+			return listener, p.errorf(decl.Pos(), "the first parameter of %s must be a context.Context", decl.Name.Name)
+		}
+		if ident, ok := sel.X.(*ast.Ident); !ok || p.importPath(ident.Name) != "context" {
+			return listener, p.errorf(decl.Pos(), "the first parameter of %s must be a context.Context", decl.Name.Name)
+		}
+		listener.withContext = true
+	default:
+		return listener, p.errorf(decl.Pos(), "%s must accept an event, optionally preceded by a context.Context", decl.Name.Name)
+	}
+
+	eventType, err := p.typeString(params[len(params)-1])
+	if err != nil {
+		return listener, err
+	}
+	listener.eventType = eventType
+
+	if decl.Type.Results != nil {
+		if decl.Type.Results.NumFields() != 1 {
+			return listener, p.errorf(decl.Pos(), "%s must return nothing, a bool or an error", decl.Name.Name)
+		}
+
+		ident, ok := decl.Type.Results.List[0].Type.(*ast.Ident)
+		if !ok || (ident.Name != "bool" && ident.Name != "error") {
+			return listener, p.errorf(decl.Pos(), "%s must return nothing, a bool or an error", decl.Name.Name)
+		}
+		listener.result = ident.Name
+	}
+
+	return listener, nil
+}
+
+// typeString prints a type expression and records the imports it refers to.
+func (p *fileParser) typeString(expr ast.Expr) (string, error) {
+	var err error
+	ast.Inspect(expr, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			path := p.importPath(ident.Name)
+			if path == "" {
+				err = p.errorf(sel.Pos(), "unknown package %s: give its import an explicit name if the package cannot be loaded", ident.Name)
+				return false
+			}
+			p.pkg.imports[path] = ident.Name
+		}
+
+		return false
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, p.fset, expr); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// importPath returns the path of the import with the given name in the file.
+func (p *fileParser) importPath(name string) string {
+	for _, spec := range p.file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		importName := ""
+		if spec.Name != nil {
+			importName = spec.Name.Name
+		} else {
+			importName = p.pkg.packageName(path)
+		}
+
+		if importName == name {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// packageName returns the name of the package with the given import path, or "" if it cannot be
+// loaded. The name of a package need not match the last element of its path, as with
+// "github.com/org/lib/v2" or "gopkg.in/yaml.v3", so the package is loaded from the module of the
+// parsed package.
+func (pkg *packageInfo) packageName(path string) string {
+	if name, ok := pkg.packageNames[path]; ok {
+		return name
+	}
+
+	// The go command resolves modules from its working directory
+	ctxt := build.Default
+	ctxt.Dir = pkg.dir
+
+	name := ""
+	if path == eventImportPath {
+		name = "event"
+	} else if loaded, err := ctxt.Import(path, pkg.dir, 0); err == nil {
+		name = loaded.Name
+	} else if first, _, _ := strings.Cut(path, "/"); !strings.Contains(first, ".") {
+		// Standard library packages are named after the last element of their path
+		name = filepath.Base(path)
+	}
+
+	pkg.packageNames[path] = name
+	return name
+}
+
+// errorf returns an error prefixed with the source position.
+func (p *fileParser) errorf(pos token.Pos, format string, args ...interface{}) error {
+	return fmt.Errorf("%s: %s", p.fset.Position(pos), fmt.Sprintf(format, args...))
+}
+
+// directive returns the arguments of the first comment line starting with the directive.
+func directive(doc *ast.CommentGroup, name string) ([]string, bool) {
+	if doc == nil {
+		return nil, false
+	}
+
+	for _, comment := range doc.List {
+		if args, ok := parseDirective(comment.Text, name); ok {
+			return args, true
+		}
+	}
+
+	return nil, false
+}
+
+// parseDirective returns the arguments of a comment line if it starts with the directive.
+func parseDirective(text, name string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(text, name)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil, false
+	}
+
+	return strings.Fields(rest), true
+}
+
+// receiverType returns the type name of a method receiver and whether it is a pointer.
+func receiverType(expr ast.Expr) (string, bool) {
+	pointer := false
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+		pointer = true
+	}
+
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name, pointer
+	}
+
+	return "", false
+}
@@ -0,0 +1,230 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoHandler is returned when a command is sent without a registered handler.
+	ErrNoHandler = errors.New("event: no handler registered for command")
+
+	// ErrHandlerExists is returned when a second handler is registered for a command.
+	ErrHandlerExists = errors.New("event: command handler already registered")
+
+	// ErrNilHandler is returned when a nil command handler is registered.
+	ErrNilHandler = errors.New("event: command handler is nil")
+)
+
+// CommandHandler handles a command and returns its result.
+type CommandHandler interface {
+	// HandleCommand handles the given command within the given context.
+	HandleCommand(ctx context.Context, cmd Event) (interface{}, error)
+}
+
+// CommandHandlerFunc is a function that implements the CommandHandler interface.
+type CommandHandlerFunc func(context.Context, Event) (interface{}, error)
+
+// HandleCommand implements the CommandHandler interface for CommandHandlerFunc.
+func (f CommandHandlerFunc) HandleCommand(ctx context.Context, cmd Event) (interface{}, error) {
+	return f(ctx, cmd)
+}
+
+// CommandBus sends commands to exactly one handler each and returns its result to the caller.
+//
+// Commands are events, identified by their name. The bus shares the toolkit of EventDispatcher:
+// it runs the same middleware and links the metadata of commands and the events dispatched while
+// handling them. It honors the dispatcher options as follows:
+//
+//   - WithRecoveryPolicy, WithPanicHandler and WithFrozenArguments apply as they do to listeners.
+//   - WithDispatchTimeout bounds the context given to the handler. As the sender needs the
+//     handler's result, Send still waits for the handler to return.
+//   - WithSlowListenerHandler reports slow handlers, with the command name and a zero Listener.
+//   - WithTimeoutPolicy has nothing to decide, since a command has a single handler.
+//   - WithDeadLetters is ignored: the sender receives the handler's error, so failed commands
+//     are never dead-lettered.
+//
+// Middleware added with Use wraps the handler call itself, so the bus has no listener middleware.
+type CommandBus struct {
+	dispatcher *EventDispatcher
+	mu         sync.RWMutex
+	handlers   map[string]*commandEntry
+}
+
+// commandEntry is a handler registration. Its address identifies the registration,
+// so that a subscription never removes a handler registered after it.
+type commandEntry struct {
+	handler CommandHandler
+}
+
+// NewCommandBus creates a new command bus.
+func NewCommandBus(opts ...DispatcherOption) *CommandBus {
+	return &CommandBus{
+		dispatcher: NewDispatcher(opts...),
+		handlers:   make(map[string]*commandEntry),
+	}
+}
+
+// Register registers the handler of the named command and returns a subscription to remove it.
+// It returns ErrHandlerExists if the command already has a handler.
+func (b *CommandBus) Register(commandName string, handler CommandHandler) (*Subscription, error) {
+	if handler == nil {
+		return nil, ErrNilHandler
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.handlers[commandName]; ok {
+		return nil, fmt.Errorf("%w: %q", ErrHandlerExists, commandName)
+	}
+	entry := &commandEntry{handler: handler}
+	b.handlers[commandName] = entry
+
+	return newSubscription(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if b.handlers[commandName] == entry {
+			delete(b.handlers, commandName)
+		}
+	}), nil
+}
+
+// HasHandler reports whether a handler is registered for the named command.
+func (b *CommandBus) HasHandler(commandName string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, ok := b.handlers[commandName]
+	return ok
+}
+
+// Use adds middleware that wraps every command. Middleware added first runs outermost.
+func (b *CommandBus) Use(middleware ...Middleware) {
+	b.dispatcher.Use(middleware...)
+}
+
+// UseFor adds middleware that wraps the commands matching the command name or pattern.
+func (b *CommandBus) UseFor(commandName string, middleware ...Middleware) {
+	b.dispatcher.UseFor(commandName, middleware...)
+}
+
+// Send passes the command to its handler through the middleware and returns the handler's result
+// and error. It returns ErrNoHandler if no handler is registered for the command.
+//
+// The handler's context is bounded by the dispatch timeout of the bus, if any. A panicking handler
+// is handled according to the recovery policy of the bus: under PanicPropagate the panic is
+// re-raised once the middleware has returned, otherwise it is returned as a *ListenerPanicError.
+func (b *CommandBus) Send(ctx context.Context, cmd Event) (interface{}, error) {
+	b.mu.RLock()
+	entry, ok := b.handlers[cmd.Name()]
+	b.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoHandler, cmd.Name())
+	}
+
+	d := b.dispatcher
+	dispatchMiddleware, _ := d.registry.Load().middlewareFor(cmd.Name())
+
+	ctx = withCurrentEvent(ctx, cmd)
+
+	if f, ok := cmd.(freezer); ok && d.freeze {
+		f.Freeze()
+	}
+
+	var result interface{}
+	var panicErr *ListenerPanicError
+	next := chain(func(ctx context.Context, cmd Event) error {
+		var err error
+		result, err = d.handleCommand(ctx, entry.handler, cmd)
+		errors.As(err, &panicErr)
+		return err
+	}, dispatchMiddleware)
+
+	err := next(ctx, cmd)
+
+	// Re-raise a handler panic once every middleware has returned
+	if panicErr != nil && d.recovery == PanicPropagate {
+		panic(panicErr.Value)
+	}
+
+	return result, err
+}
+
+// handleCommand calls the command handler within the dispatch timeout and reports it if it was slow.
+func (d *EventDispatcher) handleCommand(ctx context.Context, handler CommandHandler, cmd Event) (interface{}, error) {
+	if d.dispatchTimeout <= 0 && d.slowHandler == nil {
+		return d.callHandler(ctx, handler, cmd)
+	}
+
+	ctx, cancel := d.withDispatchTimeout(ctx)
+	defer cancel()
+
+	start := time.Now()
+	result, err := d.callHandler(ctx, handler, cmd)
+	d.reportSlow(cmd, ListenerPriority{}, time.Since(start))
+
+	return result, err
+}
+
+// callHandler invokes the command handler and converts a panic into a *ListenerPanicError.
+func (d *EventDispatcher) callHandler(ctx context.Context, handler CommandHandler, cmd Event) (result interface{}, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = d.panicked(&ListenerPanicError{
+				EventName: cmd.Name(),
+				Handler:   handler,
+				Value:     v,
+				Stack:     debug.Stack(),
+			})
+		}
+	}()
+
+	return handler.HandleCommand(ctx, cmd)
+}
+
+// RegisterCommand registers fn as the handler of the named command, passing the command as C.
+// Sending a command of another type under that name fails with a *TypeMismatchError.
+func RegisterCommand[C Event, R any](bus *CommandBus, commandName string, fn func(context.Context, C) (R, error)) (*Subscription, error) {
+	return bus.Register(commandName, CommandHandlerFunc(func(ctx context.Context, cmd Event) (interface{}, error) {
+		typed, ok := cmd.(C)
+		if !ok {
+			return nil, &TypeMismatchError{
+				EventName: cmd.Name(),
+				Expected:  reflect.TypeFor[C](),
+				Actual:    reflect.TypeOf(cmd),
+			}
+		}
+
+		return fn(ctx, typed)
+	}))
+}
+
+// SendCommand sends the command and returns the result of its handler as R.
+// A result of another type fails with a *TypeMismatchError.
+func SendCommand[R any](ctx context.Context, bus *CommandBus, cmd Event) (R, error) {
+	var zero R
+
+	result, err := bus.Send(ctx, cmd)
+	if result == nil {
+		return zero, err
+	}
+
+	typed, ok := result.(R)
+	if !ok {
+		return zero, errors.Join(err, &TypeMismatchError{
+			EventName: cmd.Name(),
+			Expected:  reflect.TypeFor[R](),
+			Actual:    reflect.TypeOf(result),
+		})
+	}
+
+	return typed, err
+}
@@ -0,0 +1,243 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandBus_Send(t *testing.T) {
+	bus := event.NewCommandBus()
+
+	_, err := bus.Register("order.create", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		id, _ := cmd.(*event.BaseEvent).Get("id")
+		return "ORD-" + id.(string), nil
+	}))
+	require.NoError(t, err)
+
+	result, err := bus.Send(context.Background(), event.NewEvent("order.create", map[string]interface{}{"id": "1"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, "ORD-1", result)
+}
+
+func TestCommandBus_ReturnsHandlerError(t *testing.T) {
+	bus := event.NewCommandBus()
+	errOutOfStock := errors.New("out of stock")
+
+	_, err := bus.Register("order.create", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		return nil, errOutOfStock
+	}))
+	require.NoError(t, err)
+
+	_, err = bus.Send(context.Background(), event.NewEvent("order.create"))
+
+	assert.Equal(t, errOutOfStock, err)
+}
+
+func TestCommandBus_MissingHandler(t *testing.T) {
+	bus := event.NewCommandBus()
+
+	_, err := bus.Send(context.Background(), event.NewEvent("order.create"))
+
+	assert.ErrorIs(t, err, event.ErrNoHandler)
+	assert.Contains(t, err.Error(), "order.create")
+}
+
+func TestCommandBus_DuplicateHandler(t *testing.T) {
+	bus := event.NewCommandBus()
+	handler := event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		return nil, nil
+	})
+
+	sub, err := bus.Register("order.create", handler)
+	require.NoError(t, err)
+
+	_, err = bus.Register("order.create", handler)
+	assert.ErrorIs(t, err, event.ErrHandlerExists)
+
+	_, err = bus.Register("order.cancel", nil)
+	assert.ErrorIs(t, err, event.ErrNilHandler)
+
+	// Once removed, the command can be registered again
+	sub.Unsubscribe()
+	assert.False(t, bus.HasHandler("order.create"))
+
+	_, err = bus.Register("order.create", handler)
+	require.NoError(t, err)
+
+	// A stale subscription does not remove the new handler
+	sub.Unsubscribe()
+	assert.True(t, bus.HasHandler("order.create"))
+}
+
+func TestCommandBus_Middleware(t *testing.T) {
+	bus := event.NewCommandBus()
+
+	var calls []string
+	bus.Use(recordingMiddleware(&calls, "outer"))
+	bus.UseFor("order.*", recordingMiddleware(&calls, "orders"))
+	bus.UseFor("user.*", recordingMiddleware(&calls, "users"))
+
+	_, err := bus.Register("order.create", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		calls = append(calls, "handler")
+		return 42, nil
+	}))
+	require.NoError(t, err)
+
+	result, err := bus.Send(context.Background(), event.NewEvent("order.create"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, []string{"outer:before", "orders:before", "handler", "orders:after", "outer:after"}, calls)
+}
+
+func TestCommandBus_Panic(t *testing.T) {
+	var handled *event.ListenerPanicError
+	bus := event.NewCommandBus(
+		event.WithRecoveryPolicy(event.PanicRecoverContinue),
+		event.WithPanicHandler(func(err *event.ListenerPanicError) {
+			handled = err
+		}),
+	)
+
+	_, err := bus.Register("order.create", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		panic("boom")
+	}))
+	require.NoError(t, err)
+
+	_, err = bus.Send(context.Background(), event.NewEvent("order.create"))
+
+	var panicErr *event.ListenerPanicError
+	require.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotNil(t, panicErr.Handler)
+	assert.Contains(t, panicErr.Error(), "command handler")
+	assert.Same(t, panicErr, handled)
+}
+
+func TestCommandBus_PanicPropagatesAfterMiddleware(t *testing.T) {
+	bus := event.NewCommandBus()
+
+	var calls []string
+	bus.Use(recordingMiddleware(&calls, "outer"))
+
+	_, err := bus.Register("order.create", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		panic("boom")
+	}))
+	require.NoError(t, err)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = bus.Send(context.Background(), event.NewEvent("order.create"))
+	})
+	assert.Equal(t, []string{"outer:before", "outer:after"}, calls)
+}
+
+func TestCommandBus_Metadata(t *testing.T) {
+	bus := event.NewCommandBus()
+	dispatcher := event.NewDispatcher()
+
+	var created *event.BaseEvent
+	_, err := bus.Register("order.create", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		created = event.NewEvent("order.created")
+		_, err := dispatcher.DispatchContext(ctx, created)
+		return nil, err
+	}))
+	require.NoError(t, err)
+
+	cmd := event.NewEvent("order.create")
+	_, err = bus.Send(context.Background(), cmd)
+	require.NoError(t, err)
+
+	assert.Equal(t, cmd.Metadata().ID, cmd.Metadata().CorrelationID)
+	assert.Equal(t, cmd.Metadata().CorrelationID, created.Metadata().CorrelationID)
+	assert.Equal(t, cmd.Metadata().ID, created.Metadata().CausationID)
+}
+
+type CreateOrderCommand struct {
+	*event.BaseEvent
+	CustomerID string
+}
+
+func TestCommandBus_Typed(t *testing.T) {
+	bus := event.NewCommandBus()
+
+	_, err := event.RegisterCommand(bus, "order.create", func(ctx context.Context, cmd *CreateOrderCommand) (*OrderCreatedEvent, error) {
+		return NewOrderCreatedEvent("ORD-" + cmd.CustomerID), nil
+	})
+	require.NoError(t, err)
+
+	order, err := event.SendCommand[*OrderCreatedEvent](context.Background(), bus, &CreateOrderCommand{
+		BaseEvent:  event.NewEvent("order.create"),
+		CustomerID: "7",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ORD-7", order.OrderID)
+
+	// The command and the result must have the expected types
+	var mismatch *event.TypeMismatchError
+	_, err = event.SendCommand[*OrderCreatedEvent](context.Background(), bus, event.NewEvent("order.create"))
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, "*event.BaseEvent", mismatch.Actual.String())
+
+	_, err = event.SendCommand[string](context.Background(), bus, &CreateOrderCommand{BaseEvent: event.NewEvent("order.create")})
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, "string", mismatch.Expected.String())
+}
+
+func TestCommandBus_DispatchTimeout(t *testing.T) {
+	bus := event.NewCommandBus(event.WithDispatchTimeout(10 * time.Millisecond))
+
+	_, err := bus.Register("order.create", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	require.NoError(t, err)
+
+	_, err = bus.Send(context.Background(), event.NewEvent("order.create"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCommandBus_SlowHandler(t *testing.T) {
+	var slow []event.SlowListener
+	bus := event.NewCommandBus(event.WithSlowListenerHandler(5*time.Millisecond, func(s event.SlowListener) {
+		slow = append(slow, s)
+	}))
+
+	_, err := bus.Register("order.create", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "ORD-1", nil
+	}))
+	require.NoError(t, err)
+
+	result, err := bus.Send(context.Background(), event.NewEvent("order.create"))
+	require.NoError(t, err)
+	assert.Equal(t, "ORD-1", result)
+
+	require.Len(t, slow, 1)
+	assert.Equal(t, "order.create", slow[0].EventName)
+	assert.GreaterOrEqual(t, slow[0].Duration, 10*time.Millisecond)
+}
+
+func TestCommandBus_IgnoresDeadLetters(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+	bus := event.NewCommandBus(event.WithDeadLetters(sink))
+
+	errDeclined := errors.New("card declined")
+	_, err := bus.Register("payment.charge", event.CommandHandlerFunc(func(ctx context.Context, cmd event.Event) (interface{}, error) {
+		return nil, errDeclined
+	}))
+	require.NoError(t, err)
+
+	_, err = bus.Send(context.Background(), event.NewEvent("payment.charge"))
+	assert.ErrorIs(t, err, errDeclined)
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
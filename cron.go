@@ -0,0 +1,207 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned when a schedule specification cannot be parsed.
+var ErrInvalidSchedule = errors.New("event: invalid schedule")
+
+// Schedule computes the times at which a recurring event is due.
+type Schedule interface {
+	// Next returns the first time after t at which the event is due,
+	// or the zero time if it is never due again.
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule parses a schedule specification, which is either a standard cron expression or
+// a descriptor.
+//
+// Cron expressions have five fields: minute, hour, day of month, month and day of week, where
+// Sunday is 0 or 7. Each field is "*", a number, a range such as "1-5" or a list such as "1,15",
+// optionally followed by a step such as "*/15". As in cron, an event restricted by both day fields
+// is due on the days matching either. Times are computed in the location of the given time.
+//
+// The descriptors are "@yearly" (or "@annually"), "@monthly", "@weekly", "@daily" (or "@midnight"),
+// "@hourly" and "@every <duration>", such as "@every 90s", which takes any time.ParseDuration value.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if interval, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w %q: interval must be a positive duration", ErrInvalidSchedule, spec)
+		}
+		return every(d), nil
+	}
+
+	expr := spec
+	switch spec {
+	case "@yearly", "@annually":
+		expr = "0 0 1 1 *"
+	case "@monthly":
+		expr = "0 0 1 * *"
+	case "@weekly":
+		expr = "0 0 * * 0"
+	case "@daily", "@midnight":
+		expr = "0 0 * * *"
+	case "@hourly":
+		expr = "0 * * * *"
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w %q: expected 5 fields, got %d", ErrInvalidSchedule, spec, len(fields))
+	}
+
+	var s cronSchedule
+	bounds := [5]struct{ min, max int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := [5]*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, field := range fields {
+		bits, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, spec, err)
+		}
+		*sets[i] = bits
+	}
+
+	// Sunday may be written as 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.anyDom = strings.HasPrefix(fields[2], "*")
+	s.anyDow = strings.HasPrefix(fields[4], "*")
+
+	return s, nil
+}
+
+// parseCronField parses a cron field into a set of values with bit n standing for value n.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		values, stepText, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if values != "*" {
+			first, last, isRange := strings.Cut(values, "-")
+
+			var err error
+			if lo, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+
+			switch {
+			case isRange:
+				if hi, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			case !hasStep:
+				hi = lo
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+// every is the schedule of an "@every" descriptor.
+type every time.Duration
+
+// Next implements the Schedule interface.
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cronSchedule is the schedule of a cron expression.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// anyDom and anyDow report whether the day fields are unrestricted.
+	anyDom, anyDow bool
+}
+
+// Next implements the Schedule interface.
+//
+// Times are matched on the wall clock. Times that fall in a daylight saving gap are skipped, and
+// times that repeat when daylight saving time ends match only once, at their first occurrence.
+func (s cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Add(-time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond())).Add(time.Minute)
+
+	// A valid expression matches within a few years, even when it asks for February 29
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = advance(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+		case !s.dayMatches(t):
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			next := t.Add(time.Minute)
+			if !wallClock(next).After(wallClock(t)) {
+				// The clock was set back, so skip the times already seen
+				next = advance(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+			}
+			t = next
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// advance returns next, the time.Date of a later wall-clock time than t. A wall-clock time in
+// a daylight saving gap does not exist and may be resolved to a time before t, in which case
+// advance returns the end of the gap instead.
+func advance(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+
+	if _, end := t.ZoneBounds(); end.After(t) {
+		return end
+	}
+
+	// Not expected, but time must keep moving forward
+	return t.Add(time.Minute)
+}
+
+// wallClock returns the wall-clock time of t to the minute, as a UTC time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// dayMatches reports whether the day of t matches the day of month and day of week fields.
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+
+	return dom || dow
+}
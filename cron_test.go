@@ -0,0 +1,141 @@
+package event_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 10, 17, 30, 0, time.UTC) // a Wednesday
+
+	kolkata := location(t, "Asia/Kolkata")      // +05:30
+	kathmandu := location(t, "Asia/Kathmandu")  // +05:45
+	newYork := location(t, "America/New_York")  // daylight saving time from 2024-03-10 02:00 to 2024-11-03 02:00
+	santiago := location(t, "America/Santiago") // daylight saving time from 2024-09-08 00:00
+
+	tests := []struct {
+		spec     string
+		from     time.Time // defaults to from
+		expected time.Time
+	}{
+		{spec: "* * * * *", expected: time.Date(2024, time.January, 31, 10, 18, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", expected: time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)},
+		{spec: "5 * * * *", expected: time.Date(2024, time.January, 31, 11, 5, 0, 0, time.UTC)},
+		{spec: "0 9-17/4 * * *", expected: time.Date(2024, time.January, 31, 13, 0, 0, 0, time.UTC)},
+		{spec: "30 2 * * 1-5", expected: time.Date(2024, time.February, 1, 2, 30, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", expected: time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", expected: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1,15 * *", expected: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 13 * 5", expected: time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", expected: time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{spec: "@daily", expected: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", expected: time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", expected: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@yearly", expected: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@every 90s", expected: time.Date(2024, time.January, 31, 10, 19, 0, 0, time.UTC)},
+
+		// Half and quarter hour offsets
+		{
+			spec:     "0 12 * * *",
+			from:     time.Date(2024, time.January, 31, 10, 17, 0, 0, kolkata),
+			expected: time.Date(2024, time.January, 31, 12, 0, 0, 0, kolkata),
+		},
+		{
+			spec:     "*/15 * * * *",
+			from:     time.Date(2024, time.January, 31, 10, 17, 0, 0, kolkata),
+			expected: time.Date(2024, time.January, 31, 10, 30, 0, 0, kolkata),
+		},
+		{
+			spec:     "@hourly",
+			from:     time.Date(2024, time.January, 31, 10, 17, 0, 0, kathmandu),
+			expected: time.Date(2024, time.January, 31, 11, 0, 0, 0, kathmandu),
+		},
+
+		// Daylight saving gaps are skipped
+		{
+			spec:     "30 2 * * *",
+			from:     time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork),
+			expected: time.Date(2024, time.March, 11, 2, 30, 0, 0, newYork),
+		},
+		{
+			spec:     "0 * * * *",
+			from:     time.Date(2024, time.March, 10, 1, 30, 0, 0, newYork),
+			expected: time.Date(2024, time.March, 10, 3, 0, 0, 0, newYork),
+		},
+		{
+			spec:     "0 * * * *",
+			from:     time.Date(2024, time.September, 7, 23, 30, 0, 0, santiago),
+			expected: time.Date(2024, time.September, 8, 1, 0, 0, 0, santiago),
+		},
+
+		// Repeated times match once
+		{
+			spec:     "30 1 * * *",
+			from:     time.Date(2024, time.November, 3, 1, 30, 0, 0, newYork), // the first 01:30
+			expected: time.Date(2024, time.November, 4, 1, 30, 0, 0, newYork),
+		},
+		{
+			spec:     "@hourly",
+			from:     time.Date(2024, time.November, 3, 1, 30, 0, 0, newYork),
+			expected: time.Date(2024, time.November, 3, 2, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := event.ParseSchedule(tt.spec)
+			require.NoError(t, err)
+
+			start := from
+			if !tt.from.IsZero() {
+				start = tt.from
+			}
+
+			next := schedule.Next(start)
+			assert.Truef(t, next.Equal(tt.expected), "expected %v, got %v", tt.expected, next)
+		})
+	}
+}
+
+// location loads the time zone with the given name.
+func location(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestParseSchedule_Never(t *testing.T) {
+	schedule, err := event.ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every",
+		"@every -1s",
+		"@every soon",
+		"@fortnightly",
+	} {
+		_, err := event.ParseSchedule(spec)
+		assert.ErrorIs(t, err, event.ErrInvalidSchedule, spec)
+	}
+}
@@ -0,0 +1,314 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDeadLetterNotFound is returned when a dead letter does not exist.
+	ErrDeadLetterNotFound = errors.New("event: dead letter not found")
+
+	// ErrNoDeadLetterSink is returned by Redrive when the dispatcher has no dead-letter sink.
+	ErrNoDeadLetterSink = errors.New("event: dispatcher has no dead-letter sink")
+
+	// ErrListenerNotFound is returned by Redrive when the listener of a dead letter is no longer registered.
+	ErrListenerNotFound = errors.New("event: listener not found")
+)
+
+// DeadLetter records an event that a listener failed to handle, once its retries were exhausted
+// or it failed permanently.
+type DeadLetter struct {
+	// ID identifies the dead letter.
+	ID string
+
+	// Event is the event the listener failed to handle.
+	Event Event
+
+	// Listener describes the listener that failed. Its Listener field is nil
+	// for dead letters read back from a file.
+	Listener ListenerInfo
+
+	// ListenerType is the type of the listener that failed, as printed by %T.
+	ListenerType string
+
+	// Attempts holds every failed call of the listener, oldest first.
+	Attempts []FailedAttempt
+
+	// At is the time the event was dead-lettered.
+	At time.Time
+}
+
+// FailedAttempt is a failed call of a listener.
+type FailedAttempt struct {
+	// Attempt is the number of the call, counted from one.
+	Attempt int
+
+	// At is the time the call failed.
+	At time.Time
+
+	// Err is the error of the call.
+	Err error
+}
+
+// DeadLetterSink stores dead letters until they are redriven or discarded.
+type DeadLetterSink interface {
+	// Put stores the dead letter, replacing any dead letter with the same ID.
+	Put(letter DeadLetter) error
+
+	// Get returns the dead letter with the given ID, or ErrDeadLetterNotFound.
+	Get(id string) (DeadLetter, error)
+
+	// List returns the stored dead letters in the order they were first stored.
+	List() ([]DeadLetter, error)
+
+	// Delete removes the dead letter with the given ID.
+	Delete(id string) error
+}
+
+// WithDeadLetters makes the dispatcher store a DeadLetter in the sink whenever a listener fails for
+// good: after its last retry, on a permanent error, or on its only call if it has no retry policy.
+// If the sink fails, its error is joined to the listener's error in DispatchWithResult.
+func WithDeadLetters(sink DeadLetterSink) DispatcherOption {
+	return func(d *EventDispatcher) {
+		d.deadLetters = sink
+	}
+}
+
+// Redrive calls the listener of a dead letter again with its event, for example after a fix has been
+// deployed. The listener is looked up by name if it was named. An unnamed listener is only found
+// while it is still registered with this dispatcher: listener IDs are not stable across restarts, so
+// Redrive returns ErrListenerNotFound for unnamed listeners of dead letters read back from a file.
+// The listener is called through the listener middleware and with its retry policy, but regardless
+// of its filters and run limits.
+//
+// If the listener succeeds, the dead letter is deleted. Otherwise its new failures are added to the
+// dead letter and the listener's error is returned.
+func (d *EventDispatcher) Redrive(ctx context.Context, id string) error {
+	if d.deadLetters == nil {
+		return ErrNoDeadLetterSink
+	}
+
+	letter, err := d.deadLetters.Get(id)
+	if err != nil {
+		return err
+	}
+
+	reg := d.registry.Load()
+	l, ok := reg.deadLetterListener(letter)
+	if !ok {
+		return fmt.Errorf("%w: %s for %q", ErrListenerNotFound, letter.ListenerType, letter.Event.Name())
+	}
+
+	_, middleware := reg.middlewareFor(letter.Event.Name())
+	ctx = withCurrentEvent(ctx, letter.Event)
+
+	history := &attemptHistory{attempts: letter.Attempts}
+	call := history.track(func(ctx context.Context) error {
+		return d.callWithMiddleware(ctx, l, letter.Event, middleware)
+	})
+
+	if l.retry != nil {
+		_, err = callWithRetry(ctx, letter.Event, l.retry, call)
+	} else {
+		err = call(ctx)
+	}
+
+	if err == nil {
+		return d.deadLetters.Delete(id)
+	}
+
+	letter.Attempts = history.attempts
+	letter.At = time.Now()
+	if putErr := d.deadLetters.Put(letter); putErr != nil {
+		return errors.Join(err, putErr)
+	}
+
+	return err
+}
+
+// deadLetterListener returns the registered listener that the dead letter was recorded for.
+func (r *registry) deadLetterListener(letter DeadLetter) (ListenerPriority, bool) {
+	// Without the listener itself, the ID and type of an unnamed listener may match another
+	// listener registered after a restart, such as any ListenerFunc
+	if letter.Listener.Name == "" && letter.Listener.Listener == nil {
+		return ListenerPriority{}, false
+	}
+
+	for _, l := range r.listenersFor(letter.Event.Name(), reflect.TypeOf(letter.Event)) {
+		if letter.Listener.Name != "" {
+			if l.name == letter.Listener.Name {
+				return l, true
+			}
+			continue
+		}
+
+		if l.id == letter.Listener.ID && fmt.Sprintf("%T", l.Listener) == letter.ListenerType {
+			return l, true
+		}
+	}
+
+	return ListenerPriority{}, false
+}
+
+// callWithPolicy calls the listener, retries it according to its retry policy and dead-letters it
+// if it still fails. It returns the number of calls and the error of the last one.
+func (d *EventDispatcher) callWithPolicy(ctx context.Context, l ListenerPriority, event Event, call func(context.Context) error) (int, error) {
+	history := d.newHistory()
+	call = history.track(call)
+
+	attempts, err := 1, error(nil)
+	if l.retry != nil {
+		attempts, err = callWithRetry(ctx, event, l.retry, call)
+	} else {
+		err = call(ctx)
+	}
+
+	if err != nil {
+		err = d.deadLetter(event, l, history, err)
+	}
+
+	return attempts, err
+}
+
+// deadLetter stores a dead letter for the listener's failure and returns the listener's error,
+// joined with the error of the sink if storing failed.
+func (d *EventDispatcher) deadLetter(event Event, l ListenerPriority, history *attemptHistory, err error) error {
+	if d.deadLetters == nil {
+		return err
+	}
+
+	letter := DeadLetter{
+		ID:           newEventID(),
+		Event:        event,
+		Listener:     l.info(),
+		ListenerType: fmt.Sprintf("%T", l.Listener),
+		Attempts:     history.attempts,
+		At:           time.Now(),
+	}
+
+	if putErr := d.deadLetters.Put(letter); putErr != nil {
+		return errors.Join(err, putErr)
+	}
+
+	return err
+}
+
+// newHistory returns a history for the failed calls of a listener, or nil if there is no
+// dead-letter sink to record them for.
+func (d *EventDispatcher) newHistory() *attemptHistory {
+	if d.deadLetters == nil {
+		return nil
+	}
+
+	return &attemptHistory{}
+}
+
+// attemptHistory records the failed calls of a listener.
+type attemptHistory struct {
+	attempts []FailedAttempt
+}
+
+// track returns call wrapped to record its failures. A nil history returns call as it is.
+func (h *attemptHistory) track(call func(context.Context) error) func(context.Context) error {
+	if h == nil {
+		return call
+	}
+
+	return func(ctx context.Context) error {
+		err := call(ctx)
+		if err != nil {
+			h.attempts = append(h.attempts, FailedAttempt{
+				Attempt: len(h.attempts) + 1,
+				At:      time.Now(),
+				Err:     err,
+			})
+		}
+
+		return err
+	}
+}
+
+// MemoryDeadLetterSink is a DeadLetterSink that keeps dead letters in memory.
+type MemoryDeadLetterSink struct {
+	mu      sync.RWMutex
+	letters map[string]DeadLetter
+	order   []string
+}
+
+// NewMemoryDeadLetterSink creates an empty in-memory dead-letter sink.
+func NewMemoryDeadLetterSink() *MemoryDeadLetterSink {
+	return &MemoryDeadLetterSink{letters: make(map[string]DeadLetter)}
+}
+
+// Put implements the DeadLetterSink interface.
+func (s *MemoryDeadLetterSink) Put(letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(letter)
+	return nil
+}
+
+// put stores the dead letter. The caller must hold the write lock.
+func (s *MemoryDeadLetterSink) put(letter DeadLetter) {
+	if _, ok := s.letters[letter.ID]; !ok {
+		s.order = append(s.order, letter.ID)
+	}
+
+	s.letters[letter.ID] = letter
+}
+
+// Get implements the DeadLetterSink interface.
+func (s *MemoryDeadLetterSink) Get(id string) (DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	letter, ok := s.letters[id]
+	if !ok {
+		return DeadLetter{}, fmt.Errorf("%w: %q", ErrDeadLetterNotFound, id)
+	}
+
+	return letter, nil
+}
+
+// List implements the DeadLetterSink interface.
+func (s *MemoryDeadLetterSink) List() ([]DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	letters := make([]DeadLetter, 0, len(s.order))
+	for _, id := range s.order {
+		letters = append(letters, s.letters[id])
+	}
+
+	return letters, nil
+}
+
+// Delete implements the DeadLetterSink interface.
+func (s *MemoryDeadLetterSink) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delete(id)
+	return nil
+}
+
+// delete removes the dead letter. The caller must hold the write lock.
+func (s *MemoryDeadLetterSink) delete(id string) {
+	if _, ok := s.letters[id]; !ok {
+		return
+	}
+
+	delete(s.letters, id)
+	for i, stored := range s.order {
+		if stored == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// FileDeadLetterSink is a DeadLetterSink that appends dead letters to a file of JSON lines,
+// so that they survive a restart.
+//
+// Every Put and Delete appends a record and syncs the file; opening the file replays the records.
+// Events are stored by name, arguments and metadata and read back as *BaseEvent values, so their
+// arguments must be encodable as JSON and come back with JSON types, such as float64 for numbers.
+// Errors are read back with their message only.
+type FileDeadLetterSink struct {
+	memory *MemoryDeadLetterSink
+	file   *os.File
+}
+
+// OpenFileDeadLetterSink opens the dead-letter file at the given path, creating it if needed,
+// and loads the dead letters it holds.
+func OpenFileDeadLetterSink(path string) (*FileDeadLetterSink, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileDeadLetterSink{memory: NewMemoryDeadLetterSink(), file: file}
+	if err := s.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("event: loading dead letters from %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Put implements the DeadLetterSink interface.
+func (s *FileDeadLetterSink) Put(letter DeadLetter) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	if err := s.append(deadLetterRecord{Op: "put", ID: letter.ID, Letter: encodeDeadLetter(letter)}); err != nil {
+		return err
+	}
+
+	s.memory.put(letter)
+	return nil
+}
+
+// Get implements the DeadLetterSink interface.
+func (s *FileDeadLetterSink) Get(id string) (DeadLetter, error) {
+	return s.memory.Get(id)
+}
+
+// List implements the DeadLetterSink interface.
+func (s *FileDeadLetterSink) List() ([]DeadLetter, error) {
+	return s.memory.List()
+}
+
+// Delete implements the DeadLetterSink interface.
+func (s *FileDeadLetterSink) Delete(id string) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	if _, ok := s.memory.letters[id]; !ok {
+		return nil
+	}
+
+	if err := s.append(deadLetterRecord{Op: "delete", ID: id}); err != nil {
+		return err
+	}
+
+	s.memory.delete(id)
+	return nil
+}
+
+// Close closes the file.
+func (s *FileDeadLetterSink) Close() error {
+	return s.file.Close()
+}
+
+// append writes a record to the file. The caller must hold the write lock.
+func (s *FileDeadLetterSink) append(record deadLetterRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+// load replays the records of the file. A partial last line, left by a crash while appending,
+// is cut off so that the next record starts on a line of its own.
+func (s *FileDeadLetterSink) load() error {
+	reader := bufio.NewReader(s.file)
+
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				return s.file.Truncate(offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(len(line))
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var record deadLetterRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+
+		switch record.Op {
+		case "put":
+			if record.Letter == nil {
+				return fmt.Errorf("put record %q without dead letter", record.ID)
+			}
+			s.memory.put(record.Letter.decode(record.ID))
+		case "delete":
+			s.memory.delete(record.ID)
+		default:
+			return fmt.Errorf("unknown record operation %q", record.Op)
+		}
+	}
+}
+
+// deadLetterRecord is a line of a dead-letter file.
+type deadLetterRecord struct {
+	Op     string          `json:"op"`
+	ID     string          `json:"id"`
+	Letter *deadLetterJSON `json:"letter,omitempty"`
+}
+
+// deadLetterJSON is the JSON form of a DeadLetter.
+type deadLetterJSON struct {
+	Event        eventJSON     `json:"event"`
+	ListenerID   ListenerID    `json:"listener_id"`
+	ListenerName string        `json:"listener_name,omitempty"`
+	Priority     int           `json:"priority"`
+	ListenerType string        `json:"listener_type"`
+	Attempts     []attemptJSON `json:"attempts"`
+	At           time.Time     `json:"at"`
+}
+
+// eventJSON is the JSON form of an event.
+type eventJSON struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Metadata  *Metadata              `json:"metadata,omitempty"`
+}
+
+// attemptJSON is the JSON form of a FailedAttempt.
+type attemptJSON struct {
+	Attempt int       `json:"attempt"`
+	At      time.Time `json:"at"`
+	Error   string    `json:"error"`
+}
+
+// encodeDeadLetter converts the dead letter to its JSON form.
+func encodeDeadLetter(letter DeadLetter) *deadLetterJSON {
+	record := &deadLetterJSON{
+		Event:        eventJSON{Name: letter.Event.Name(), Arguments: letter.Event.Arguments()},
+		ListenerID:   letter.Listener.ID,
+		ListenerName: letter.Listener.Name,
+		Priority:     letter.Listener.Priority,
+		ListenerType: letter.ListenerType,
+		At:           letter.At,
+	}
+
+	if me, ok := letter.Event.(MetadataEvent); ok {
+		md := snapshotMetadata(me)
+		record.Event.Metadata = &md
+	}
+
+	for _, attempt := range letter.Attempts {
+		record.Attempts = append(record.Attempts, attemptJSON{
+			Attempt: attempt.Attempt,
+			At:      attempt.At,
+			Error:   attempt.Err.Error(),
+		})
+	}
+
+	return record
+}
+
+// decode converts the JSON form back to a dead letter with the given ID.
+func (r *deadLetterJSON) decode(id string) DeadLetter {
+	e := NewEvent(r.Event.Name, r.Event.Arguments)
+	if r.Event.Metadata != nil {
+		*e.Metadata() = *r.Event.Metadata
+	}
+
+	letter := DeadLetter{
+		ID:           id,
+		Event:        e,
+		Listener:     ListenerInfo{ID: r.ListenerID, Name: r.ListenerName, Priority: r.Priority},
+		ListenerType: r.ListenerType,
+		At:           r.At,
+	}
+
+	for _, attempt := range r.Attempts {
+		letter.Attempts = append(letter.Attempts, FailedAttempt{
+			Attempt: attempt.Attempt,
+			At:      attempt.At,
+			Err:     errors.New(attempt.Error),
+		})
+	}
+
+	return letter
+}
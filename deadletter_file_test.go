@@ -0,0 +1,214 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDeadLetterSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+
+	sink, err := event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+
+	e := event.NewEvent("payment.charge", map[string]interface{}{"amount": 42, "currency": "EUR"})
+	e.Metadata().CorrelationID = "flow-1"
+	at := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, sink.Put(event.DeadLetter{
+		ID:           "first",
+		Event:        e,
+		Listener:     event.ListenerInfo{ID: 3, Name: "charge", Priority: 10},
+		ListenerType: "*payments.Charger",
+		Attempts: []event.FailedAttempt{
+			{Attempt: 1, At: at.Add(-time.Second), Err: errors.New("timeout")},
+			{Attempt: 2, At: at, Err: errors.New("timeout")},
+		},
+		At: at,
+	}))
+	require.NoError(t, sink.Put(event.DeadLetter{ID: "second", Event: event.NewEvent("email.send"), At: at}))
+	require.NoError(t, sink.Delete("second"))
+	require.NoError(t, sink.Close())
+
+	reopened, err := event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	letters, err := reopened.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+
+	letter := letters[0]
+	assert.Equal(t, "first", letter.ID)
+	assert.Equal(t, "payment.charge", letter.Event.Name())
+	assert.Equal(t, map[string]interface{}{"amount": float64(42), "currency": "EUR"}, letter.Event.Arguments())
+	assert.Equal(t, e.Metadata().ID, letter.Event.(event.MetadataEvent).Metadata().ID)
+	assert.Equal(t, "flow-1", letter.Event.(event.MetadataEvent).Metadata().CorrelationID)
+	assert.Equal(t, event.ListenerInfo{ID: 3, Name: "charge", Priority: 10}, letter.Listener)
+	assert.Equal(t, "*payments.Charger", letter.ListenerType)
+	assert.True(t, at.Equal(letter.At))
+	require.Len(t, letter.Attempts, 2)
+	assert.EqualError(t, letter.Attempts[1].Err, "timeout")
+	assert.True(t, at.Equal(letter.Attempts[1].At))
+}
+
+func TestFileDeadLetterSink_PartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+
+	sink, err := event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Put(event.DeadLetter{ID: "first", Event: event.NewEvent("a")}))
+	require.NoError(t, sink.Close())
+
+	// Simulate a crash in the middle of appending a record
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"op":"put","id":"sec`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	sink, err = event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Put(event.DeadLetter{ID: "third", Event: event.NewEvent("c")}))
+	require.NoError(t, sink.Close())
+
+	sink, err = event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 2)
+	assert.Equal(t, "first", letters[0].ID)
+	assert.Equal(t, "third", letters[1].ID)
+}
+
+func TestFileDeadLetterSink_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o644))
+
+	_, err := event.OpenFileDeadLetterSink(path)
+	assert.Error(t, err)
+}
+
+func TestFileDeadLetterSink_RedriveAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+
+	sink, err := event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+
+	dispatcher := event.NewDispatcher(event.WithDeadLetters(sink))
+	_, err = dispatcher.Listen("payment.charge", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		return errUnavailable
+	}), event.WithName("charge"))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("payment.charge", map[string]interface{}{"amount": 42}))
+	require.NoError(t, sink.Close())
+
+	// After the restart the fixed listener is registered again under the same name
+	sink, err = event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	var amount interface{}
+	restarted := event.NewDispatcher(event.WithDeadLetters(sink))
+	_, err = restarted.Listen("payment.charge", event.ListenerFunc(func(e event.Event) bool {
+		amount = e.Arguments()["amount"]
+		return true
+	}), event.WithName("charge"))
+	require.NoError(t, err)
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+
+	require.NoError(t, restarted.Redrive(context.Background(), letters[0].ID))
+	assert.Equal(t, float64(42), amount)
+
+	letters, err = sink.List()
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+func TestFileDeadLetterSink_RedriveUnnamedAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+
+	sink, err := event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+
+	dispatcher := event.NewDispatcher(event.WithDeadLetters(sink))
+	dispatcher.AddListener("payment.charge", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		return errUnavailable
+	}))
+
+	dispatcher.Dispatch(event.NewEvent("payment.charge"))
+	require.NoError(t, sink.Close())
+
+	// After the restart another listener has the same ID and type
+	sink, err = event.OpenFileDeadLetterSink(path)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	calls := 0
+	restarted := event.NewDispatcher(event.WithDeadLetters(sink))
+	restarted.AddListener("payment.charge", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		calls++
+		return nil
+	}))
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+
+	assert.ErrorIs(t, restarted.Redrive(context.Background(), letters[0].ID), event.ErrListenerNotFound)
+	assert.Zero(t, calls)
+
+	letters, err = sink.List()
+	require.NoError(t, err)
+	assert.Len(t, letters, 1)
+}
+
+func TestFileDeadLetterSink_ConcurrentHeaders(t *testing.T) {
+	sink, err := event.OpenFileDeadLetterSink(filepath.Join(t.TempDir(), "dead-letters.jsonl"))
+	require.NoError(t, err)
+	defer sink.Close()
+
+	e := event.NewEvent("payment.charge")
+	e.SetHeader("tenant", "acme")
+
+	// Headers keep changing while the letters are written
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				e.SetHeader("retry", fmt.Sprint(i))
+			}
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, sink.Put(event.DeadLetter{ID: fmt.Sprint(i), Event: e}))
+	}
+	close(stop)
+	<-done
+
+	letter, err := sink.Get("0")
+	require.NoError(t, err)
+	tenant, _ := letter.Event.(*event.BaseEvent).Header("tenant")
+	assert.Equal(t, "acme", tenant)
+}
@@ -0,0 +1,225 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failing returns a listener that fails while *broken is true and counts its calls.
+func failing(broken *bool, calls *int) event.Listener {
+	return event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		*calls++
+		if *broken {
+			return errUnavailable
+		}
+		return nil
+	})
+}
+
+func TestDeadLetter_ExhaustedRetries(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+	dispatcher := event.NewDispatcher(event.WithDeadLetters(sink))
+
+	broken, calls := true, 0
+	_, err := dispatcher.Listen("payment.charge", failing(&broken, &calls),
+		event.WithName("charge"),
+		event.WithRetry(event.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	require.NoError(t, err)
+
+	e := event.NewEvent("payment.charge", map[string]interface{}{"amount": 42})
+	result := dispatcher.DispatchWithResult(context.Background(), e)
+	require.ErrorIs(t, result.Err(), errUnavailable)
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+
+	letter := letters[0]
+	assert.Same(t, e, letter.Event)
+	assert.Equal(t, "charge", letter.Listener.Name)
+	assert.Equal(t, "event.ErrorListenerFunc", letter.ListenerType)
+	assert.False(t, letter.At.IsZero())
+	require.Len(t, letter.Attempts, 3)
+	for i, attempt := range letter.Attempts {
+		assert.Equal(t, i+1, attempt.Attempt)
+		assert.ErrorIs(t, attempt.Err, errUnavailable)
+		assert.False(t, attempt.At.After(letter.At))
+	}
+}
+
+func TestDeadLetter_PermanentFailure(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+	dispatcher := event.NewDispatcher(event.WithDeadLetters(sink))
+
+	dispatcher.AddListener("payment.charge", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		return event.Permanent(errors.New("card declined"))
+	}))
+	dispatcher.AddListener("payment.charge", event.ListenerFunc(func(e event.Event) bool {
+		return true
+	}))
+
+	dispatcher.Dispatch(event.NewEvent("payment.charge"))
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Len(t, letters[0].Attempts, 1)
+	assert.EqualError(t, letters[0].Attempts[0].Err, "card declined")
+}
+
+func TestDeadLetter_AsyncRetries(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+	dispatcher := event.NewAsyncDispatcher(event.NewDispatcher(event.WithDeadLetters(sink)), event.WithWorkers(1))
+	defer dispatcher.Shutdown(context.Background())
+
+	broken, calls := true, 0
+	_, err := dispatcher.Listen("payment.charge", failing(&broken, &calls),
+		event.WithRetry(event.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	require.NoError(t, err)
+
+	future, err := dispatcher.DispatchAsync(event.NewEvent("payment.charge"))
+	require.NoError(t, err)
+	_, err = future.Wait(context.Background())
+	require.NoError(t, err)
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Len(t, letters[0].Attempts, 2)
+}
+
+func TestDeadLetter_AsyncRetriesAbandoned(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+	dispatcher := event.NewAsyncDispatcher(event.NewDispatcher(event.WithDeadLetters(sink)), event.WithWorkers(1))
+	defer dispatcher.Shutdown(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := dispatcher.Listen("payment.charge", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		cancel()
+		return errUnavailable
+	}), event.WithRetry(event.RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}))
+	require.NoError(t, err)
+
+	future, err := dispatcher.DispatchAsyncContext(ctx, event.NewEvent("payment.charge"))
+	require.NoError(t, err)
+	_, err = future.Wait(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, event.OutcomeFailed, future.Result().Listeners[0].Outcome)
+	assert.Equal(t, 1, future.Result().Listeners[0].Attempts)
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Len(t, letters[0].Attempts, 1)
+	assert.ErrorIs(t, letters[0].Attempts[0].Err, errUnavailable)
+}
+
+func TestDeadLetter_Redrive(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+	dispatcher := event.NewDispatcher(event.WithDeadLetters(sink))
+
+	broken, calls := true, 0
+	other := 0
+	dispatcher.AddListener("payment.charge", failing(&broken, &calls))
+	dispatcher.AddListener("payment.charge", event.ListenerFunc(func(e event.Event) bool {
+		other++
+		return true
+	}))
+
+	dispatcher.Dispatch(event.NewEvent("payment.charge"))
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	id := letters[0].ID
+
+	// Still failing: the new failure is added to the dead letter
+	err = dispatcher.Redrive(context.Background(), id)
+	require.ErrorIs(t, err, errUnavailable)
+
+	letter, err := sink.Get(id)
+	require.NoError(t, err)
+	require.Len(t, letter.Attempts, 2)
+	assert.Equal(t, 2, letter.Attempts[1].Attempt)
+
+	// Fixed: only the failed listener is called and the dead letter is deleted
+	broken = false
+	require.NoError(t, dispatcher.Redrive(context.Background(), id))
+
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 1, other)
+
+	_, err = sink.Get(id)
+	assert.ErrorIs(t, err, event.ErrDeadLetterNotFound)
+	assert.ErrorIs(t, dispatcher.Redrive(context.Background(), id), event.ErrDeadLetterNotFound)
+}
+
+func TestDeadLetter_RedriveListenerNotFound(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+	dispatcher := event.NewDispatcher(event.WithDeadLetters(sink))
+
+	broken, calls := true, 0
+	sub, err := dispatcher.Listen("payment.charge", failing(&broken, &calls))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("payment.charge"))
+	sub.Unsubscribe()
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+
+	err = dispatcher.Redrive(context.Background(), letters[0].ID)
+	assert.ErrorIs(t, err, event.ErrListenerNotFound)
+
+	assert.ErrorIs(t, event.NewDispatcher().Redrive(context.Background(), letters[0].ID), event.ErrNoDeadLetterSink)
+}
+
+type failingSink struct {
+	*event.MemoryDeadLetterSink
+	err error
+}
+
+func (s failingSink) Put(letter event.DeadLetter) error {
+	return s.err
+}
+
+func TestDeadLetter_SinkError(t *testing.T) {
+	errSink := errors.New("disk full")
+	dispatcher := event.NewDispatcher(event.WithDeadLetters(failingSink{event.NewMemoryDeadLetterSink(), errSink}))
+
+	broken, calls := true, 0
+	dispatcher.AddListener("payment.charge", failing(&broken, &calls))
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("payment.charge"))
+
+	assert.ErrorIs(t, result.Err(), errUnavailable)
+	assert.ErrorIs(t, result.Err(), errSink)
+}
+
+func TestMemoryDeadLetterSink(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, sink.Put(event.DeadLetter{ID: id, Event: event.NewEvent(id)}))
+	}
+	require.NoError(t, sink.Put(event.DeadLetter{ID: "a", Event: event.NewEvent("a2")}))
+	require.NoError(t, sink.Delete("b"))
+	require.NoError(t, sink.Delete("missing"))
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+
+	var names []string
+	for _, letter := range letters {
+		names = append(names, letter.Event.Name())
+	}
+	assert.Equal(t, []string{"a2", "c"}, names)
+}
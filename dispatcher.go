@@ -1,14 +1,20 @@
 package event
 
 import (
-	"sort"
+	"context"
+	"errors"
+	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Dispatcher manages event listeners and dispatches events to them.
 type Dispatcher interface {
 	// AddListener adds a listener for the specified event.
 	// The priority determines the order of execution of listeners, higher values mean earlier execution.
+	// The event name may be a pattern such as "user.*" or "order.**", see IsPattern.
 	AddListener(eventName string, listener Listener, priority ...int)
 
 	// HasListener checks if a listener is registered for the specified event.
@@ -22,52 +28,108 @@ type Dispatcher interface {
 }
 
 // EventDispatcher is the default implementation of the Dispatcher interface.
+//
+// Dispatching reads an immutable snapshot of the listeners without locking, so registering or
+// removing listeners never blocks a dispatch in progress and only affects later dispatches.
 type EventDispatcher struct {
-	listeners map[string]EventListeners
-	mu        sync.RWMutex
+	registry     atomic.Pointer[registry]
+	lastID       ListenerID
+	mu           sync.Mutex // serializes changes to the registry
+	recovery     RecoveryPolicy
+	panicHandler PanicHandler
+	freeze       bool
+	deadLetters  DeadLetterSink
+
+	dispatchTimeout time.Duration
+	timeoutPolicy   TimeoutPolicy
+	slowThreshold   time.Duration
+	slowHandler     func(SlowListener)
+}
+
+// DispatcherOption configures an EventDispatcher.
+type DispatcherOption func(*EventDispatcher)
+
+// freezer is implemented by events whose arguments can be made read-only, such as BaseEvent.
+type freezer interface {
+	Freeze()
+}
+
+// WithFrozenArguments makes the dispatcher freeze the arguments of every event implementing
+// Freeze, such as BaseEvent, before the first listener runs.
+func WithFrozenArguments() DispatcherOption {
+	return func(d *EventDispatcher) {
+		d.freeze = true
+	}
 }
 
 // NewDispatcher creates a new event dispatcher.
-func NewDispatcher() *EventDispatcher {
-	return &EventDispatcher{
-		listeners: make(map[string]EventListeners),
+func NewDispatcher(opts ...DispatcherOption) *EventDispatcher {
+	d := &EventDispatcher{}
+	d.registry.Store(newRegistry())
+
+	for _, opt := range opts {
+		opt(d)
 	}
+
+	return d
 }
 
 // AddListener adds a listener for the specified event.
 func (d *EventDispatcher) AddListener(eventName string, listener Listener, priority ...int) {
+	// Default priority is 0
+	var opts listenerOptions
+	if len(priority) > 0 {
+		opts.priority = priority[0]
+	}
+
+	// Without ordering constraints, registration cannot fail
+	_, _ = d.add(eventName, listener, opts)
+}
+
+// add registers a listener for the specified event and returns its ID, or an *OrderCycleError
+// if its ordering constraints contradict those of the listeners already registered.
+//
+// Listeners with equal priorities run in registration order, unless constraints require otherwise.
+func (d *EventDispatcher) add(eventName string, listener Listener, opts listenerOptions) (ListenerID, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	// Default priority is 0
-	p := 0
-	if len(priority) > 0 {
-		p = priority[0]
+	l := ListenerPriority{
+		Listener: listener,
+		Priority: opts.priority,
+		id:       d.lastID + 1,
+		owner:    opts.owner,
+		name:     opts.name,
+		before:   opts.before,
+		after:    opts.after,
+		filters:  opts.filters,
+		retry:    opts.retry,
+		timeout:  opts.timeout,
 	}
 
-	// Initialize the listener slice if it doesn't exist
-	if _, ok := d.listeners[eventName]; !ok {
-		d.listeners[eventName] = make(EventListeners, 0)
+	if err := d.checkOrder(eventName, l); err != nil {
+		return 0, err
 	}
 
-	// Add the listener with its priority
-	d.listeners[eventName] = append(d.listeners[eventName], ListenerPriority{
-		Listener: listener,
-		Priority: p,
-	})
+	d.lastID++
+	l.limit = d.newLimit(eventName, l.id, opts)
+	set := d.listenerSet(eventName)
 
-	// Sort listeners by priority (higher first)
-	sort.Sort(d.listeners[eventName])
+	// The constraints were checked above, so the listeners can be ordered
+	ordered, _ := orderListeners(append(slices.Clip(set.load()), l))
+	set.store(ordered)
+
+	return d.lastID, nil
 }
 
 // HasListener checks if a listener is registered for the specified event.
+//
+// Listeners are compared with ==. Listeners that are not comparable, such as ListenerFunc
+// values, are never found; use the Subscription returned by Listen to manage them.
 func (d *EventDispatcher) HasListener(eventName string, listener Listener) bool {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
-
-	if eventListeners, ok := d.listeners[eventName]; ok {
-		for _, registered := range eventListeners {
-			if registered.Listener == listener {
+	if set, ok := d.registry.Load().listeners[eventName]; ok {
+		for _, registered := range set.load() {
+			if sameValue(registered.Listener, listener) {
 				return true
 			}
 		}
@@ -77,46 +139,361 @@ func (d *EventDispatcher) HasListener(eventName string, listener Listener) bool
 }
 
 // RemoveListener removes a listener from the specified event.
+//
+// Listeners are compared with ==. Listeners that are not comparable, such as ListenerFunc
+// values, are never removed; use the Subscription returned by Listen to remove them.
 func (d *EventDispatcher) RemoveListener(eventName string, listener Listener) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	if eventListeners, ok := d.listeners[eventName]; ok {
-		newListeners := make(EventListeners, 0, len(eventListeners))
+	d.removeWhere(eventName, func(registered ListenerPriority) bool {
+		return sameValue(registered.Listener, listener)
+	})
+}
+
+// Dispatch dispatches an event to all registered listeners.
+//
+// Dispatch takes no lock. It does not allocate either as long as the dispatcher has no pattern
+// listeners, no listeners registered with On, no dispatch timeout and no middleware for the event,
+// and the listeners of the event take no context and have no retry policy or timeout. Matching
+// patterns and event types, and merging their listeners, allocates on every dispatch.
+func (d *EventDispatcher) Dispatch(event Event) Event {
+	_ = d.dispatch(context.Background(), event)
+	return event
+}
+
+// DispatchContext dispatches an event to all registered listeners within the given context.
+//
+// Listeners implementing ContextListener receive the context. Once the context is done,
+// no further listeners are called and the cause of the cancellation is returned. Likewise, when
+// a listener times out under TimeoutStop, its *ListenerError is returned.
+//
+// A deadline of the context bounds the dispatch as WithDispatchTimeout does: the dispatch stops
+// waiting for a listener still running at the deadline, which records OutcomeTimedOut.
+//
+// Events that a listener dispatches with the context it received inherit the correlation ID
+// of the event being handled, see MetadataEvent.
+func (d *EventDispatcher) DispatchContext(ctx context.Context, event Event) (Event, error) {
+	return event, d.dispatch(ctx, event)
+}
 
-		for _, registered := range eventListeners {
-			if registered.Listener != listener {
-				newListeners = append(newListeners, registered)
+// dispatch calls the listeners for the event without recording their results and returns
+// the error that stopped the dispatch. Middleware needs the results, so dispatch defers to
+// DispatchWithResult when middleware applies to the event.
+func (d *EventDispatcher) dispatch(ctx context.Context, event Event) error {
+	reg := d.registry.Load()
+	if reg.hasMiddlewareFor(event.Name()) {
+		return d.stoppedBy(d.DispatchWithResult(ctx, event))
+	}
+
+	ctx, cancel := d.withDispatchTimeout(ctx)
+	defer cancel()
+
+	linkMetadata(ctx, event)
+
+	if f, ok := event.(freezer); ok && d.freeze {
+		f.Freeze()
+	}
+
+	// Only listeners taking a context need the current event in it, so it is added on first use
+	var listenerCtx context.Context
+
+	for _, l := range reg.listenersFor(event.Name(), reflect.TypeOf(event)) {
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+
+		if l.filters != nil && l.rejectedBy(event) != nil {
+			continue
+		}
+
+		if l.limit != nil && !l.limit.claim() {
+			continue
+		}
+
+		callCtx := ctx
+		if takesContext(l.Listener) {
+			if listenerCtx == nil {
+				listenerCtx = context.WithValue(ctx, currentEventKey{}, event)
 			}
+			callCtx = listenerCtx
 		}
 
-		d.listeners[eventName] = newListeners
+		var err error
+		if l.retry == nil && d.deadLetters == nil {
+			err = d.callListener(callCtx, l, event)
+		} else {
+			_, err = d.callWithPolicy(callCtx, l, event, func(ctx context.Context) error {
+				return d.callListener(ctx, l, event)
+			})
+		}
+
+		if err != nil {
+			var panicErr *ListenerPanicError
+			if errors.As(err, &panicErr) {
+				if d.recovery == PanicPropagate {
+					panic(panicErr.Value)
+				}
+				if d.recovery != PanicRecoverContinue {
+					return nil
+				}
+			}
+
+			if timedOut(err) && d.timeoutPolicy == TimeoutStop {
+				if ctx.Err() != nil {
+					return context.Cause(ctx)
+				}
+				return &ListenerError{EventName: event.Name(), Listener: l.Listener, Err: err}
+			}
+		}
+
+		if event.IsPropagationStopped() {
+			return nil
+		}
 	}
+
+	return nil
 }
 
-// Dispatch dispatches an event to all registered listeners.
-func (d *EventDispatcher) Dispatch(event Event) Event {
-	d.mu.RLock()
-	eventListeners, ok := d.listeners[event.Name()]
-	d.mu.RUnlock()
+// DispatchWithResult dispatches an event within the given context and records the outcome,
+// error and duration of every listener.
+func (d *EventDispatcher) DispatchWithResult(ctx context.Context, event Event) *DispatchResult {
+	result := d.dispatchWithResult(ctx, event)
+
+	// Re-raise a listener panic once every middleware has returned
+	if panicErr := d.propagated(result); panicErr != nil {
+		panic(panicErr.Value)
+	}
+
+	return result
+}
+
+// dispatchWithResult is DispatchWithResult without re-raising listener panics.
+func (d *EventDispatcher) dispatchWithResult(ctx context.Context, event Event) *DispatchResult {
+	result := &DispatchResult{Event: event}
+	reg := d.registry.Load()
+	dispatchMiddleware, listenerMiddleware := reg.middlewareFor(event.Name())
+
+	ctx, cancel := d.withDispatchTimeout(ctx)
+	defer cancel()
+
+	ctx = withCurrentEvent(ctx, event)
 
-	if !ok {
-		return event
+	if f, ok := event.(freezer); ok && d.freeze {
+		f.Freeze()
 	}
 
-	// Make a copy to avoid concurrent modification issues
-	listenersCopy := make(EventListeners, len(eventListeners))
-	copy(listenersCopy, eventListeners)
+	var listenersErr error
+	handler := chain(func(ctx context.Context, e Event) error {
+		result.Event = e
+		d.callListeners(ctx, reg, e, listenerMiddleware, result)
+		listenersErr = result.Err()
+		return listenersErr
+	}, dispatchMiddleware)
+
+	if err := handler(ctx, event); err != nil && err != listenersErr {
+		result.MiddlewareErr = err
+	}
+
+	return result
+}
+
+// propagated returns the listener panic to re-raise under PanicPropagate, if any.
+func (d *EventDispatcher) propagated(result *DispatchResult) *ListenerPanicError {
+	if d.recovery != PanicPropagate {
+		return nil
+	}
+
+	for _, l := range result.Listeners {
+		var panicErr *ListenerPanicError
+		if l.Outcome == OutcomePanicked && errors.As(l.Err, &panicErr) {
+			return panicErr
+		}
+	}
+
+	return nil
+}
+
+// callListeners calls each listener for the event in priority order and records the results.
+func (d *EventDispatcher) callListeners(ctx context.Context, reg *registry, event Event, middleware []Middleware, result *DispatchResult) {
+	listeners := reg.listenersFor(event.Name(), reflect.TypeOf(event))
+	if len(listeners) == 0 {
+		return
+	}
+
+	result.Listeners = make([]ListenerResult, len(listeners))
+	halted := false
 
 	// Call each listener in priority order
-	for _, l := range listenersCopy {
-		l.Listener.Handle(event)
+	for i, l := range listeners {
+		lr := &result.Listeners[i]
+		lr.Listener = l.Listener
+		lr.Priority = l.Priority
+
+		// Skip the rest if propagation is stopped, or a listener panicked or timed out
+		if result.PropagationStopped || halted {
+			lr.Outcome = OutcomeSkipped
+			continue
+		}
+
+		// Skip the rest if the context has been canceled or its deadline exceeded
+		if result.ContextErr == nil && ctx.Err() != nil {
+			result.ContextErr = context.Cause(ctx)
+		}
+		if result.ContextErr != nil {
+			lr.Outcome = OutcomeCanceled
+			continue
+		}
+
+		if filter := l.rejectedBy(event); filter != nil {
+			lr.Outcome = OutcomeFiltered
+			lr.Filter = filter
+			continue
+		}
+
+		if l.limit != nil && !l.limit.claim() {
+			lr.Outcome = OutcomeSkipped
+			continue
+		}
+
+		start := time.Now()
+		err := d.callWithRetry(ctx, l, event, middleware, lr)
+		lr.Duration = time.Since(start)
+
+		switch record(lr, l, event, err) {
+		case OutcomePanicked:
+			halted = d.recovery != PanicRecoverContinue
+		case OutcomeTimedOut:
+			// Once the dispatch timed out, the rest are canceled rather than skipped
+			halted = d.timeoutPolicy == TimeoutStop && ctx.Err() == nil
+		}
 
-		// Stop if propagation is stopped
 		if event.IsPropagationStopped() {
-			break
+			result.PropagationStopped = true
 		}
 	}
+}
 
-	return event
+// record records the outcome of a listener call and returns it.
+func record(lr *ListenerResult, l ListenerPriority, event Event, err error) Outcome {
+	lr.Outcome = OutcomeHandled
+	lr.Err = nil
+
+	if err != nil {
+		lr.Outcome = OutcomeFailed
+		lr.Err = &ListenerError{EventName: event.Name(), Listener: l.Listener, Err: err}
+
+		var panicErr *ListenerPanicError
+		switch {
+		case errors.As(err, &panicErr):
+			lr.Outcome = OutcomePanicked
+		case timedOut(err):
+			lr.Outcome = OutcomeTimedOut
+		}
+	}
+
+	return lr.Outcome
+}
+
+// callWithRetry calls the listener through its middleware, retries it according to its retry policy
+// and dead-letters it if it still fails. During an asynchronous dispatch the retries are left to run
+// in the background once the listeners have been called, and they update the listener result when
+// they are done.
+func (d *EventDispatcher) callWithRetry(ctx context.Context, l ListenerPriority, event Event, middleware []Middleware, lr *ListenerResult) error {
+	lr.Attempts = 1
+	if l.retry == nil && d.deadLetters == nil {
+		return d.callWithMiddleware(ctx, l, event, middleware)
+	}
+
+	call := func(ctx context.Context) error {
+		return d.callWithMiddleware(ctx, l, event, middleware)
+	}
+
+	background, ok := ctx.Value(backgroundRetriesKey{}).(*backgroundRetries)
+	if l.retry == nil || !ok || !sameValue(background.event, event) {
+		var err error
+		lr.Attempts, err = d.callWithPolicy(ctx, l, event, call)
+		return err
+	}
+
+	history := d.newHistory()
+	call = history.track(call)
+
+	start := time.Now()
+	err := callAttempt(ctx, event, 1, call)
+	if err != nil && l.retry.MaxAttempts > 1 && l.retry.retryable(err) {
+		background.add(&pendingRetry{
+			attempt:  1,
+			err:      err,
+			duration: time.Since(start),
+			policy:   l.retry,
+			ctx:      context.WithoutCancel(ctx),
+			call: func(ctx context.Context) error {
+				// The dispatch timeout bounds each background retry afresh
+				ctx, cancel := d.withDispatchTimeout(ctx)
+				defer cancel()
+
+				return call(ctx)
+			},
+			record: func(err error, duration time.Duration, attempts int, final bool) {
+				if err != nil && final {
+					err = d.deadLetter(event, l, history, err)
+				}
+
+				record(lr, l, event, err)
+				lr.Duration = duration
+				lr.Attempts = attempts
+			},
+		})
+		return err
+	}
+
+	if err != nil {
+		err = d.deadLetter(event, l, history, err)
+	}
+
+	return err
+}
+
+// callWithMiddleware calls the listener wrapped in the listener middleware.
+func (d *EventDispatcher) callWithMiddleware(ctx context.Context, l ListenerPriority, event Event, middleware []Middleware) error {
+	if len(middleware) == 0 {
+		return d.callListener(ctx, l, event)
+	}
+
+	ctx = context.WithValue(ctx, listenerInfoKey{}, l.info())
+	handler := chain(func(ctx context.Context, e Event) error {
+		return d.callListener(ctx, l, e)
+	}, middleware)
+
+	return handler(ctx, event)
+}
+
+// handle calls the listener through the most specific interface it implements
+// and converts its outcome to an error.
+func handle(ctx context.Context, listener Listener, event Event) error {
+	switch l := listener.(type) {
+	case ErrorListener:
+		return l.HandleEvent(ctx, event)
+	case ContextListener:
+		if !l.HandleContext(ctx, event) {
+			return ErrListenerFailed
+		}
+	default:
+		if !l.Handle(event) {
+			return ErrListenerFailed
+		}
+	}
+
+	return nil
+}
+
+// takesContext reports whether the listener receives the context of the dispatch.
+func takesContext(listener Listener) bool {
+	switch listener.(type) {
+	case ErrorListener, ContextListener:
+		return true
+	default:
+		return false
+	}
 }
@@ -1,6 +1,8 @@
 package event_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/parsilver/event"
@@ -107,3 +109,68 @@ func TestDispatcher_Priority(t *testing.T) {
 
 	assert.Equal(t, []int{3, 2, 1}, callOrder)
 }
+
+type ctxKey struct{}
+
+func TestDispatcher_DispatchContextPassesContext(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var received interface{}
+	dispatcher.AddListener("user.created", event.ContextListenerFunc(func(ctx context.Context, e event.Event) bool {
+		received = ctx.Value(ctxKey{})
+		return true
+	}))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request-42")
+	_, err := dispatcher.DispatchContext(ctx, event.NewEvent("user.created"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "request-42", received)
+}
+
+func TestDispatcher_DispatchContextStopsWhenCanceled(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	dispatcher.AddListener("user.created", event.ContextListenerFunc(func(ctx context.Context, e event.Event) bool {
+		cancel()
+		return true
+	}), 100)
+
+	listener := &TestListener{}
+	dispatcher.AddListener("user.created", listener, 50)
+
+	_, err := dispatcher.DispatchContext(ctx, event.NewEvent("user.created"))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, listener.called)
+}
+
+func TestDispatcher_DispatchContextReportsCause(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	listener := &TestListener{}
+	dispatcher.AddListener("user.created", listener)
+
+	cause := errors.New("client disconnected")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+
+	_, err := dispatcher.DispatchContext(ctx, event.NewEvent("user.created"))
+
+	assert.ErrorIs(t, err, cause)
+	assert.False(t, listener.called)
+}
+
+func TestAsContextListener(t *testing.T) {
+	listener := &TestListener{}
+	e := event.NewEvent("user.created")
+
+	assert.True(t, event.AsContextListener(listener).HandleContext(context.Background(), e))
+	assert.True(t, listener.called)
+
+	fn := event.ContextListenerFunc(func(ctx context.Context, e event.Event) bool {
+		return false
+	})
+	assert.False(t, event.AsContextListener(fn).HandleContext(context.Background(), e))
+	assert.False(t, fn.Handle(e))
+}
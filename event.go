@@ -1,5 +1,15 @@
 package event
 
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrArgumentsFrozen is returned when the arguments of a frozen event are modified.
+var ErrArgumentsFrozen = errors.New("event: arguments are frozen")
+
 // Event represents an event that can be dispatched and listened to.
 type Event interface {
 	// Name returns the name of the event.
@@ -16,24 +26,38 @@ type Event interface {
 }
 
 // BaseEvent provides a basic implementation of the Event interface.
+//
+// BaseEvent is safe for concurrent use: propagation state is atomic, arguments are accessed
+// through Get, Set, Delete and Snapshot and headers through Header and SetHeader under a lock,
+// and the metadata is linked once, on the first dispatch.
 type BaseEvent struct {
 	name               string
+	mu                 sync.RWMutex
 	arguments          map[string]interface{}
-	propagationStopped bool
+	frozen             atomic.Bool
+	propagationStopped atomic.Bool
+	metadata           Metadata
+	linked             sync.Once
 }
 
 // NewEvent creates a new event with the given name and optional arguments.
+// The event is given a unique ID and the current time as occurrence time.
 func NewEvent(name string, arguments ...map[string]interface{}) *BaseEvent {
-	var args map[string]interface{}
+	args := make(map[string]interface{})
 	if len(arguments) > 0 {
-		args = arguments[0]
-	} else {
-		args = make(map[string]interface{})
+		for key, value := range arguments[0] {
+			args[key] = value
+		}
 	}
 
 	return &BaseEvent{
 		name:      name,
 		arguments: args,
+		metadata: Metadata{
+			ID:         newEventID(),
+			OccurredAt: time.Now(),
+			Headers:    make(map[string]string),
+		},
 	}
 }
 
@@ -42,17 +66,126 @@ func (e *BaseEvent) Name() string {
 	return e.name
 }
 
-// Arguments returns the arguments for the event.
+// Arguments returns a copy of the arguments for the event.
+// Changes to the returned map do not affect the event; use Set and Delete instead.
 func (e *BaseEvent) Arguments() map[string]interface{} {
-	return e.arguments
+	return e.Snapshot()
+}
+
+// Get returns the argument with the given key.
+func (e *BaseEvent) Get(key string) (interface{}, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	value, ok := e.arguments[key]
+	return value, ok
+}
+
+// Set sets the argument with the given key.
+// It returns ErrArgumentsFrozen if the event has been frozen.
+func (e *BaseEvent) Set(key string, value interface{}) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.frozen.Load() {
+		return ErrArgumentsFrozen
+	}
+
+	if e.arguments == nil {
+		e.arguments = make(map[string]interface{})
+	}
+	e.arguments[key] = value
+	return nil
+}
+
+// Delete removes the argument with the given key.
+// It returns ErrArgumentsFrozen if the event has been frozen.
+func (e *BaseEvent) Delete(key string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.frozen.Load() {
+		return ErrArgumentsFrozen
+	}
+
+	delete(e.arguments, key)
+	return nil
+}
+
+// Snapshot returns a copy of the arguments for the event.
+func (e *BaseEvent) Snapshot() map[string]interface{} {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	snapshot := make(map[string]interface{}, len(e.arguments))
+	for key, value := range e.arguments {
+		snapshot[key] = value
+	}
+
+	return snapshot
+}
+
+// Freeze makes the arguments read-only. Set and Delete fail with ErrArgumentsFrozen afterwards.
+func (e *BaseEvent) Freeze() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.frozen.Store(true)
+}
+
+// IsFrozen returns whether the arguments of the event are read-only.
+func (e *BaseEvent) IsFrozen() bool {
+	return e.frozen.Load()
 }
 
 // StopPropagation stops the propagation of the event to further listeners.
 func (e *BaseEvent) StopPropagation() {
-	e.propagationStopped = true
+	e.propagationStopped.Store(true)
 }
 
 // IsPropagationStopped returns whether the propagation of this event has been stopped.
 func (e *BaseEvent) IsPropagationStopped() bool {
-	return e.propagationStopped
+	return e.propagationStopped.Load()
+}
+
+// Metadata returns the metadata of the event.
+func (e *BaseEvent) Metadata() *Metadata {
+	return &e.metadata
+}
+
+// Header returns the metadata header with the given key.
+func (e *BaseEvent) Header(key string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	value, ok := e.metadata.Headers[key]
+	return value, ok
+}
+
+// SetHeader sets the metadata header with the given key. Headers are not arguments,
+// so they can be set on a frozen event.
+func (e *BaseEvent) SetHeader(key, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.metadata.Headers == nil {
+		e.metadata.Headers = make(map[string]string)
+	}
+	e.metadata.Headers[key] = value
+}
+
+// snapshotMetadata returns a copy of the metadata, taking the headers under the lock that
+// SetHeader holds.
+func (e *BaseEvent) snapshotMetadata() Metadata {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.metadata.clone()
+}
+
+// linkMetadata links the metadata of the event the first time it is dispatched.
+func (e *BaseEvent) linkMetadata(parent *Metadata) {
+	e.linked.Do(func() {
+		link(&e.metadata, parent)
+	})
 }
@@ -1,6 +1,7 @@
 package event_test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/parsilver/event"
@@ -33,3 +34,96 @@ func TestEvent_StopPropagation(t *testing.T) {
 
 	assert.True(t, e.IsPropagationStopped())
 }
+
+func TestEvent_ArgumentsReturnsCopy(t *testing.T) {
+	payload := map[string]interface{}{"user_id": 123}
+	e := event.NewEvent("user.created", payload)
+
+	e.Arguments()["user_id"] = 456
+	payload["user_id"] = 789
+
+	value, ok := e.Get("user_id")
+	assert.True(t, ok)
+	assert.Equal(t, 123, value)
+}
+
+func TestEvent_SetGetDelete(t *testing.T) {
+	e := event.NewEvent("user.created")
+
+	assert.NoError(t, e.Set("email", "john@example.com"))
+	value, ok := e.Get("email")
+	assert.True(t, ok)
+	assert.Equal(t, "john@example.com", value)
+
+	assert.NoError(t, e.Delete("email"))
+	_, ok = e.Get("email")
+	assert.False(t, ok)
+}
+
+func TestEvent_ZeroValue(t *testing.T) {
+	e := &event.BaseEvent{}
+
+	assert.NoError(t, e.Set("email", "john@example.com"))
+	value, ok := e.Get("email")
+	assert.True(t, ok)
+	assert.Equal(t, "john@example.com", value)
+
+	assert.NoError(t, e.Delete("email"))
+	assert.Empty(t, e.Arguments())
+}
+
+func TestEvent_Snapshot(t *testing.T) {
+	e := event.NewEvent("user.created", map[string]interface{}{"user_id": 123})
+
+	snapshot := e.Snapshot()
+	assert.NoError(t, e.Set("user_id", 456))
+
+	assert.Equal(t, 123, snapshot["user_id"])
+}
+
+func TestEvent_Freeze(t *testing.T) {
+	e := event.NewEvent("user.created", map[string]interface{}{"user_id": 123})
+
+	e.Freeze()
+
+	assert.True(t, e.IsFrozen())
+	assert.ErrorIs(t, e.Set("user_id", 456), event.ErrArgumentsFrozen)
+	assert.ErrorIs(t, e.Delete("user_id"), event.ErrArgumentsFrozen)
+
+	value, _ := e.Get("user_id")
+	assert.Equal(t, 123, value)
+}
+
+func TestEvent_ConcurrentAccess(t *testing.T) {
+	e := event.NewEvent("user.created")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = e.Set("counter", i)
+			_, _ = e.Get("counter")
+			_ = e.Snapshot()
+			e.StopPropagation()
+			_ = e.IsPropagationStopped()
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, e.IsPropagationStopped())
+}
+
+func TestDispatcher_WithFrozenArguments(t *testing.T) {
+	dispatcher := event.NewDispatcher(event.WithFrozenArguments())
+
+	var err error
+	dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+		err = e.(*event.BaseEvent).Set("user_id", 456)
+		return true
+	}))
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.ErrorIs(t, err, event.ErrArgumentsFrozen)
+}
@@ -0,0 +1,48 @@
+// Code generated by eventgen. DO NOT EDIT.
+
+package examples
+
+import (
+	"context"
+
+	"github.com/parsilver/event"
+)
+
+// Event names.
+const (
+	// EventOrderCreated is the name of OrderCreatedEvent events.
+	EventOrderCreated = "order.created"
+)
+
+// NewOrderCreatedEvent creates a new OrderCreatedEvent.
+func NewOrderCreatedEvent(orderID string, customerID string, amount float64) *OrderCreatedEvent {
+	return &OrderCreatedEvent{
+		BaseEvent:  event.NewEvent(EventOrderCreated),
+		OrderID:    orderID,
+		CustomerID: customerID,
+		Amount:     amount,
+	}
+}
+
+// GetSubscribedEvents implements the event.Subscriber interface for NotificationSubscriber.
+func (s *NotificationSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return map[string][]event.SubscriberConfig{
+		EventOrderCreated: {
+			{Method: "OnOrderCreated", Priority: 10},
+		},
+	}
+}
+
+// BindListeners implements the event.BoundSubscriber interface for NotificationSubscriber.
+func (s *NotificationSubscriber) BindListeners() map[string][]event.BoundListener {
+	return map[string][]event.BoundListener{
+		EventOrderCreated: {
+			{
+				Config: event.SubscriberConfig{Method: "OnOrderCreated", Priority: 10},
+				Listener: event.TypedContextListener(func(ctx context.Context, e *OrderCreatedEvent) error {
+					return s.OnOrderCreated(ctx, e)
+				}),
+			},
+		},
+	}
+}
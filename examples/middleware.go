@@ -1,6 +1,9 @@
 package examples
 
+//go:generate go run github.com/parsilver/event/cmd/eventgen
+
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -8,46 +11,30 @@ import (
 	"github.com/parsilver/event"
 )
 
-// LoggingMiddleware is a listener that logs all events
-type LoggingMiddleware struct{}
-
-func (m *LoggingMiddleware) Handle(e event.Event) bool {
-	log.Printf("[EVENT] %s occurred at %s", e.Name(), time.Now().Format(time.RFC3339))
-	return true
-}
-
-// TimingMiddleware measures how long event processing takes
-type TimingMiddleware struct{}
-
-func (m *TimingMiddleware) Handle(e event.Event) bool {
-	start := time.Now()
-
-	// We return true to continue processing
-	// The actual timing will be logged after all listeners have processed
-
-	// Store the start time in the event arguments for later retrieval
-	args := e.Arguments()
-	args["_timing_start"] = start
-
-	return true
+// LoggingMiddleware logs every event before it is dispatched
+func LoggingMiddleware(next event.Handler) event.Handler {
+	return func(ctx context.Context, e event.Event) error {
+		log.Printf("[EVENT] %s occurred at %s", e.Name(), time.Now().Format(time.RFC3339))
+		return next(ctx, e)
+	}
 }
 
-// TimingCleanupMiddleware calculates and logs the time taken
-type TimingCleanupMiddleware struct{}
-
-func (m *TimingCleanupMiddleware) Handle(e event.Event) bool {
-	args := e.Arguments()
-	if startTime, ok := args["_timing_start"].(time.Time); ok {
-		duration := time.Since(startTime)
-		log.Printf("[TIMING] Event %s took %v to process", e.Name(), duration)
+// TimingMiddleware measures how long event processing takes.
+// The timing is logged even if a listener stops propagation, fails or panics.
+func TimingMiddleware(next event.Handler) event.Handler {
+	return func(ctx context.Context, e event.Event) error {
+		start := time.Now()
+		defer func() {
+			log.Printf("[TIMING] Event %s took %v to process", e.Name(), time.Since(start))
+		}()
 
-		// Clean up our internal timing data
-		delete(args, "_timing_start")
+		return next(ctx, e)
 	}
-	return true
 }
 
 // OrderCreatedEvent is a custom event
+//
+//event:name order.created
 type OrderCreatedEvent struct {
 	*event.BaseEvent
 	OrderID    string
@@ -55,16 +42,6 @@ type OrderCreatedEvent struct {
 	Amount     float64
 }
 
-// NewOrderCreatedEvent creates a new OrderCreatedEvent
-func NewOrderCreatedEvent(orderID, customerID string, amount float64) *OrderCreatedEvent {
-	return &OrderCreatedEvent{
-		BaseEvent:  event.NewEvent("order.created"),
-		OrderID:    orderID,
-		CustomerID: customerID,
-		Amount:     amount,
-	}
-}
-
 // OrderProcessor processes orders
 type OrderProcessor struct{}
 
@@ -81,41 +58,31 @@ func (p *OrderProcessor) Handle(e event.Event) bool {
 // NotificationSubscriber sends notifications for various events
 type NotificationSubscriber struct{}
 
-func (s *NotificationSubscriber) OnOrderCreated(e event.Event) bool {
-	if order, ok := e.(*OrderCreatedEvent); ok {
-		// Simulate notification sending
-		time.Sleep(30 * time.Millisecond)
-		fmt.Printf("Sending order confirmation for Order #%s to customer %s\n",
-			order.OrderID, order.CustomerID)
-	}
-	return true
-}
-
-func (s *NotificationSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
-	return map[string][]event.SubscriberConfig{
-		"order.created": {
-			{
-				Method:   "OnOrderCreated",
-				Priority: 10,
-			},
-		},
-	}
+// OnOrderCreated sends an order confirmation
+//
+//event:listen order.created priority=10
+func (s *NotificationSubscriber) OnOrderCreated(ctx context.Context, order *OrderCreatedEvent) error {
+	// Simulate notification sending
+	time.Sleep(30 * time.Millisecond)
+	fmt.Printf("Sending order confirmation for Order #%s to customer %s\n",
+		order.OrderID, order.CustomerID)
+	return nil
 }
 
 func MiddlewareExample() {
 	// Create a dispatcher
 	dispatcher := event.NewDispatcher()
 
-	// Add middleware with very high and very low priorities to wrap all other listeners
-	dispatcher.AddListener("order.created", &TimingMiddleware{}, 1000)         // Run first
-	dispatcher.AddListener("order.created", &LoggingMiddleware{}, 900)         // Run second
-	dispatcher.AddListener("order.created", &TimingCleanupMiddleware{}, -1000) // Run last
+	// Add middleware that wraps every dispatch
+	dispatcher.Use(TimingMiddleware, LoggingMiddleware)
 
 	// Add business logic listeners
-	dispatcher.AddListener("order.created", &OrderProcessor{}, 100)
+	dispatcher.AddListener(EventOrderCreated, &OrderProcessor{}, 100)
 
 	// Add subscribers
-	event.RegisterSubscriber(dispatcher, &NotificationSubscriber{})
+	if _, err := event.RegisterSubscriber(dispatcher, &NotificationSubscriber{}); err != nil {
+		log.Fatal(err)
+	}
 
 	// Create and dispatch an event
 	orderEvent := NewOrderCreatedEvent("ORD-12345", "CUST-789", 99.99)
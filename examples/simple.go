@@ -2,6 +2,7 @@ package examples
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/parsilver/event"
@@ -70,7 +71,9 @@ func SimpleExample() {
 
 	// Register a subscriber
 	emailSubscriber := &EmailSubscriber{}
-	event.RegisterSubscriber(dispatcher, emailSubscriber)
+	if _, err := event.RegisterSubscriber(dispatcher, emailSubscriber); err != nil {
+		log.Fatal(err)
+	}
 
 	// Create and dispatch an event
 	userEvent := NewUserCreatedEvent(1, "johndoe", "john@example.com")
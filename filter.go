@@ -0,0 +1,189 @@
+package event
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Filter decides whether a listener is called for an event.
+//
+// The filters created by ArgEquals, ArgIn, ArgBetween and ArgType describe themselves through
+// fmt.Stringer, which makes the Filter of a ListenerResult readable in traces.
+type Filter interface {
+	// Match reports whether the listener should be called for the event.
+	Match(e Event) bool
+}
+
+// FilterFunc is a predicate that implements the Filter interface.
+type FilterFunc func(Event) bool
+
+// Match implements the Filter interface for FilterFunc.
+func (f FilterFunc) Match(e Event) bool {
+	return f(e)
+}
+
+// Where calls the listener only for events matching every filter. For other events the listener
+// is skipped without being called, and DispatchWithResult records it with OutcomeFiltered.
+// Skipped calls do not count towards the limits set with Once and Times.
+func Where(filters ...Filter) ListenerOption {
+	return func(o *listenerOptions) {
+		o.filters = append(o.filters, filters...)
+	}
+}
+
+// ArgEquals matches events whose argument with the given key equals the value, compared with ==.
+func ArgEquals(key string, value interface{}) Filter {
+	return argEquals{key: key, value: value}
+}
+
+// ArgIn matches events whose argument with the given key equals one of the values, compared with ==.
+func ArgIn(key string, values ...interface{}) Filter {
+	return argIn{key: key, values: values}
+}
+
+// ArgBetween matches events whose argument with the given key is a number from min to max inclusive.
+// Arguments of any integer or floating-point type are compared as float64.
+func ArgBetween(key string, min, max float64) Filter {
+	return argBetween{key: key, min: min, max: max}
+}
+
+// ArgType matches events whose argument with the given key is of type T,
+// or implements T if T is an interface type.
+func ArgType[T any](key string) Filter {
+	return argType{key: key, typ: reflect.TypeFor[T]()}
+}
+
+// argumentGetter is implemented by events that look up single arguments without copying them all,
+// such as BaseEvent.
+type argumentGetter interface {
+	Get(key string) (interface{}, bool)
+}
+
+// argument returns the argument of the event with the given key.
+func argument(e Event, key string) (interface{}, bool) {
+	if getter, ok := e.(argumentGetter); ok {
+		return getter.Get(key)
+	}
+
+	value, ok := e.Arguments()[key]
+	return value, ok
+}
+
+// argEquals is the filter created by ArgEquals.
+type argEquals struct {
+	key   string
+	value interface{}
+}
+
+// Match implements the Filter interface.
+func (f argEquals) Match(e Event) bool {
+	value, ok := argument(e, f.key)
+	return ok && sameValue(value, f.value)
+}
+
+// String describes the filter.
+func (f argEquals) String() string {
+	return fmt.Sprintf("%s == %#v", f.key, f.value)
+}
+
+// argIn is the filter created by ArgIn.
+type argIn struct {
+	key    string
+	values []interface{}
+}
+
+// Match implements the Filter interface.
+func (f argIn) Match(e Event) bool {
+	value, ok := argument(e, f.key)
+	if !ok {
+		return false
+	}
+
+	for _, v := range f.values {
+		if sameValue(value, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String describes the filter.
+func (f argIn) String() string {
+	values := make([]string, len(f.values))
+	for i, v := range f.values {
+		values[i] = fmt.Sprintf("%#v", v)
+	}
+
+	return fmt.Sprintf("%s in (%s)", f.key, strings.Join(values, ", "))
+}
+
+// argBetween is the filter created by ArgBetween.
+type argBetween struct {
+	key      string
+	min, max float64
+}
+
+// Match implements the Filter interface.
+func (f argBetween) Match(e Event) bool {
+	value, ok := argument(e, f.key)
+	if !ok {
+		return false
+	}
+
+	number, ok := toFloat(value)
+	return ok && number >= f.min && number <= f.max
+}
+
+// String describes the filter.
+func (f argBetween) String() string {
+	return fmt.Sprintf("%s between %v and %v", f.key, f.min, f.max)
+}
+
+// toFloat converts a value of any integer or floating-point type to float64.
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// argType is the filter created by ArgType.
+type argType struct {
+	key string
+	typ reflect.Type
+}
+
+// Match implements the Filter interface.
+func (f argType) Match(e Event) bool {
+	value, ok := argument(e, f.key)
+	if !ok || value == nil {
+		return false
+	}
+
+	return matchesType(f.typ, reflect.TypeOf(value))
+}
+
+// String describes the filter.
+func (f argType) String() string {
+	return fmt.Sprintf("%s is %s", f.key, f.typ)
+}
+
+// rejectedBy returns the first filter of the listener that the event does not match, or nil.
+func (l ListenerPriority) rejectedBy(event Event) Filter {
+	for _, filter := range l.filters {
+		if !filter.Match(event) {
+			return filter
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package event_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Declarative(t *testing.T) {
+	e := event.NewEvent("order.created", map[string]interface{}{
+		"tenant": "acme",
+		"amount": 42,
+		"ratio":  0.5,
+		"tags":   []string{"new"},
+		"err":    fmt.Errorf("declined"),
+	})
+
+	tests := map[string]struct {
+		filter event.Filter
+		match  bool
+	}{
+		"equals":                  {event.ArgEquals("tenant", "acme"), true},
+		"equals other value":      {event.ArgEquals("tenant", "globex"), false},
+		"equals missing argument": {event.ArgEquals("region", "eu"), false},
+		"equals uncomparable":     {event.ArgEquals("tags", []string{"new"}), false},
+		"in":                      {event.ArgIn("tenant", "globex", "acme"), true},
+		"not in":                  {event.ArgIn("tenant", "globex", "initech"), false},
+		"between integer":         {event.ArgBetween("amount", 10, 100), true},
+		"between bounds":          {event.ArgBetween("amount", 42, 42), true},
+		"outside range":           {event.ArgBetween("amount", 50, 100), false},
+		"between float":           {event.ArgBetween("ratio", 0, 1), true},
+		"between non-number":      {event.ArgBetween("tenant", 0, 1), false},
+		"type":                    {event.ArgType[string]("tenant"), true},
+		"other type":              {event.ArgType[int64]("amount"), false},
+		"interface type":          {event.ArgType[error]("err"), true},
+		"type missing argument":   {event.ArgType[string]("region"), false},
+		"func":                    {event.FilterFunc(func(e event.Event) bool { return e.Name() == "order.created" }), true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.match, tt.filter.Match(e))
+		})
+	}
+}
+
+func TestFilter_String(t *testing.T) {
+	assert.Equal(t, `tenant == "acme"`, fmt.Sprint(event.ArgEquals("tenant", "acme")))
+	assert.Equal(t, `tenant in ("acme", "globex")`, fmt.Sprint(event.ArgIn("tenant", "acme", "globex")))
+	assert.Equal(t, "amount between 10 and 99.5", fmt.Sprint(event.ArgBetween("amount", 10, 99.5)))
+	assert.Equal(t, "user is *event.BaseEvent", fmt.Sprint(event.ArgType[*event.BaseEvent]("user")))
+}
+
+func TestFilter_SkipsNonMatchingListeners(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls []string
+	_, err := dispatcher.Listen("order.created", recorder(&calls, "acme"), event.Where(event.ArgEquals("tenant", "acme")))
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("order.created", recorder(&calls, "large"),
+		event.Where(event.ArgEquals("tenant", "acme"), event.ArgBetween("amount", 1000, 1e9)))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("order.created", map[string]interface{}{"tenant": "globex", "amount": 5000}))
+	dispatcher.Dispatch(event.NewEvent("order.created", map[string]interface{}{"tenant": "acme", "amount": 10}))
+
+	assert.Equal(t, []string{"acme"}, calls)
+}
+
+func TestFilter_RecordedInResult(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	filter := event.ArgEquals("tenant", "acme")
+
+	_, err := dispatcher.Listen("order.created", recorder(new([]string), "acme"), event.Where(filter))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	require.Len(t, result.Listeners, 1)
+	assert.Equal(t, event.OutcomeFiltered, result.Listeners[0].Outcome)
+	assert.Equal(t, "filtered", result.Listeners[0].Outcome.String())
+	assert.Equal(t, filter, result.Listeners[0].Filter)
+	assert.False(t, result.Listeners[0].Ran())
+	assert.NoError(t, result.Err())
+}
+
+func TestFilter_DoesNotCountTowardsLimit(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls []string
+	_, err := dispatcher.Listen("user.verified", recorder(&calls, "verified"),
+		event.Once(), event.Where(event.ArgEquals("user", 7)))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("user.verified", map[string]interface{}{"user": 3}))
+	dispatcher.Dispatch(event.NewEvent("user.verified", map[string]interface{}{"user": 7}))
+	dispatcher.Dispatch(event.NewEvent("user.verified", map[string]interface{}{"user": 7}))
+
+	assert.Equal(t, []string{"verified"}, calls)
+}
+
+type tenantSubscriber struct {
+	calls int
+}
+
+func (s *tenantSubscriber) OnOrderCreated(e event.Event) {
+	s.calls++
+}
+
+func (s *tenantSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return map[string][]event.SubscriberConfig{
+		"order.created": {
+			{Method: "OnOrderCreated", Filters: []event.Filter{event.ArgIn("tenant", "acme", "initech")}},
+		},
+	}
+}
+
+func TestFilter_SubscriberConfig(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &tenantSubscriber{}
+
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("order.created", map[string]interface{}{"tenant": "acme"}))
+	dispatcher.Dispatch(event.NewEvent("order.created", map[string]interface{}{"tenant": "globex"}))
+
+	assert.Equal(t, 1, subscriber.calls)
+}
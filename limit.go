@@ -0,0 +1,103 @@
+package event
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Once removes the listener after it has run once.
+func Once() ListenerOption {
+	return Times(1)
+}
+
+// Times removes the listener after it has run n times. Values lower than one are ignored.
+//
+// The runs are counted atomically, so concurrent dispatches never run the listener more than n times.
+func Times(n int) ListenerOption {
+	return func(o *listenerOptions) {
+		if n > 0 {
+			o.times = int64(n)
+		}
+	}
+}
+
+// Until removes the listener at the deadline. Dispatches from then on no longer run it.
+func Until(deadline time.Time) ListenerOption {
+	return func(o *listenerOptions) {
+		o.until = deadline
+	}
+}
+
+// listenerLimit bounds how many times and until when a listener runs.
+type listenerLimit struct {
+	// remaining is the number of runs left, or negative when the runs are not limited.
+	remaining atomic.Int64
+
+	// deadline is the time from which the listener no longer runs, or zero for none.
+	deadline time.Time
+
+	// timer removes the listener at the deadline. It is guarded by the dispatcher's lock.
+	timer *time.Timer
+
+	// remove unregisters the listener.
+	remove func()
+}
+
+// newLimit creates the limit of a listener registration, or returns nil if the options set none.
+// The caller must hold the write lock.
+func (d *EventDispatcher) newLimit(eventName string, id ListenerID, opts listenerOptions) *listenerLimit {
+	if opts.times == 0 && opts.until.IsZero() {
+		return nil
+	}
+
+	limit := &listenerLimit{
+		deadline: opts.until,
+		remove: func() {
+			d.remove(eventName, id)
+		},
+	}
+
+	limit.remaining.Store(-1)
+	if opts.times > 0 {
+		limit.remaining.Store(opts.times)
+	}
+
+	if !opts.until.IsZero() {
+		limit.timer = time.AfterFunc(time.Until(opts.until), limit.remove)
+	}
+
+	return limit
+}
+
+// claim reports whether the listener may run now and counts the run. The run that reaches
+// the limit removes the listener, and later claims fail even on snapshots that still hold it.
+func (l *listenerLimit) claim() bool {
+	if !l.deadline.IsZero() && !time.Now().Before(l.deadline) {
+		l.remove()
+		return false
+	}
+
+	for {
+		n := l.remaining.Load()
+		if n < 0 {
+			return true
+		}
+		if n == 0 {
+			return false
+		}
+
+		if l.remaining.CompareAndSwap(n, n-1) {
+			if n == 1 {
+				l.remove()
+			}
+			return true
+		}
+	}
+}
+
+// stop releases the timer of the limit. The caller must hold the write lock.
+func (l *listenerLimit) stop() {
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+}
@@ -0,0 +1,172 @@
+package event_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimit_Once(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	listener := event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	})
+
+	_, err := dispatcher.Listen("user.verified", listener, event.Once())
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("user.verified"))
+	dispatcher.Dispatch(event.NewEvent("user.verified"))
+
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, dispatcher.Listeners("user.verified"))
+}
+
+func TestLimit_Times(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	_, err := dispatcher.Listen("user.verified", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}), event.Times(3))
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		dispatcher.Dispatch(event.NewEvent("user.verified"))
+	}
+
+	assert.Equal(t, 3, calls)
+}
+
+func TestLimit_TimesIgnoresNonPositiveCount(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	_, err := dispatcher.Listen("user.verified", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}), event.Times(0))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("user.verified"))
+	dispatcher.Dispatch(event.NewEvent("user.verified"))
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestLimit_OnceUnderConcurrentDispatch(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls atomic.Int32
+	_, err := dispatcher.Listen("user.verified", event.ListenerFunc(func(e event.Event) bool {
+		calls.Add(1)
+		return true
+	}), event.Times(5))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dispatcher.Dispatch(event.NewEvent("user.verified"))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(5), calls.Load())
+	assert.Empty(t, dispatcher.Listeners("user.verified"))
+}
+
+func TestLimit_Until(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	_, err := dispatcher.Listen("user.verified", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}), event.Until(time.Now().Add(50*time.Millisecond)))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("user.verified"))
+	assert.Equal(t, 1, calls)
+
+	// The listener is removed at the deadline, without waiting for another dispatch
+	assert.Eventually(t, func() bool {
+		return len(dispatcher.Listeners("user.verified")) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	dispatcher.Dispatch(event.NewEvent("user.verified"))
+	assert.Equal(t, 1, calls)
+}
+
+func TestLimit_UntilInThePast(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	_, err := dispatcher.Listen("user.verified", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}), event.Until(time.Now().Add(-time.Second)))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("user.verified"))
+
+	assert.Zero(t, calls)
+}
+
+func TestLimit_ExhaustedListenerIsSkippedInResult(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var nested *event.DispatchResult
+	dispatching := false
+	dispatcher.AddListener("user.verified", event.ListenerFunc(func(e event.Event) bool {
+		// The nested dispatch uses up the listener that the outer dispatch calls next
+		if !dispatching {
+			dispatching = true
+			nested = dispatcher.DispatchWithResult(context.Background(), event.NewEvent("user.verified"))
+		}
+		return true
+	}), 10)
+
+	calls := 0
+	_, err := dispatcher.Listen("user.verified", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}), event.Once())
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("user.verified"))
+
+	assert.Equal(t, 1, calls)
+	require.Len(t, nested.Listeners, 2)
+	assert.Equal(t, event.OutcomeHandled, nested.Listeners[1].Outcome)
+	require.Len(t, result.Listeners, 2)
+	assert.Equal(t, event.OutcomeSkipped, result.Listeners[1].Outcome)
+}
+
+func TestLimit_UnsubscribeBeforeLimit(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	sub, err := dispatcher.Listen("user.verified", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}), event.Times(3), event.Until(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	sub.Unsubscribe()
+	dispatcher.Dispatch(event.NewEvent("user.verified"))
+
+	assert.Zero(t, calls)
+}
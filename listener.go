@@ -1,5 +1,10 @@
 package event
 
+import (
+	"context"
+	"time"
+)
+
 // Listener is the interface that must be implemented by event listeners.
 type Listener interface {
 	// Handle handles the given event.
@@ -21,14 +26,44 @@ func (f ListenerFunc) Handle(e Event) bool {
 type ListenerPriority struct {
 	Listener Listener
 	Priority int
+
+	// id identifies the registration. IDs increase with registration order, which is used
+	// to order listeners of equal priority when exact and pattern listeners are merged.
+	id ListenerID
+
+	// owner is the subscriber that registered the listener, if any.
+	owner interface{}
+
+	// name identifies the listener in the Before and After constraints of other listeners.
+	name string
+
+	// before and after name the listeners this listener must run before and after.
+	before []string
+	after  []string
+
+	// limit bounds how often and how long the listener runs, or is nil for no bound.
+	limit *listenerLimit
+
+	// filters must all match an event for the listener to run.
+	filters []Filter
+
+	// retry retries the listener when it fails, or is nil for no retries.
+	retry *RetryPolicy
+
+	// timeout bounds the time the listener may take, or is zero for no bound.
+	timeout time.Duration
 }
 
 // EventListeners represents a collection of listeners for an event.
 type EventListeners []ListenerPriority
 
-// Less is used for sorting listeners by priority in descending order.
+// Less is used for sorting listeners by priority in descending order,
+// keeping registration order among listeners with the same priority.
 func (el EventListeners) Less(i, j int) bool {
-	return el[i].Priority > el[j].Priority
+	if el[i].Priority != el[j].Priority {
+		return el[i].Priority > el[j].Priority
+	}
+	return el[i].id < el[j].id
 }
 
 // Swap swaps two listeners.
@@ -40,3 +75,67 @@ func (el EventListeners) Swap(i, j int) {
 func (el EventListeners) Len() int {
 	return len(el)
 }
+
+// ContextListener is implemented by listeners that need the context of the dispatch.
+//
+// The dispatcher calls HandleContext instead of Handle for listeners implementing this interface.
+// The context carries request-scoped values, cancellation and deadlines from DispatchContext.
+type ContextListener interface {
+	// HandleContext handles the given event within the given context.
+	HandleContext(ctx context.Context, e Event) bool
+}
+
+// ContextListenerFunc is a function that implements both the Listener and ContextListener interfaces.
+type ContextListenerFunc func(context.Context, Event) bool
+
+// Handle implements the Listener interface for ContextListenerFunc using a background context.
+func (f ContextListenerFunc) Handle(e Event) bool {
+	return f(context.Background(), e)
+}
+
+// HandleContext implements the ContextListener interface for ContextListenerFunc.
+func (f ContextListenerFunc) HandleContext(ctx context.Context, e Event) bool {
+	return f(ctx, e)
+}
+
+// contextAdapter adapts a Listener to the ContextListener interface by ignoring the context.
+type contextAdapter struct {
+	listener Listener
+}
+
+// HandleContext implements the ContextListener interface for contextAdapter.
+func (a contextAdapter) HandleContext(_ context.Context, e Event) bool {
+	return a.listener.Handle(e)
+}
+
+// AsContextListener returns the listener as a ContextListener.
+// Listeners that do not implement ContextListener are adapted and simply ignore the context.
+func AsContextListener(listener Listener) ContextListener {
+	if cl, ok := listener.(ContextListener); ok {
+		return cl
+	}
+
+	return contextAdapter{listener: listener}
+}
+
+// ErrorListener is implemented by listeners that report failures as errors.
+//
+// The dispatcher calls HandleEvent instead of Handle or HandleContext for listeners implementing
+// this interface, and records the returned error in the DispatchResult.
+type ErrorListener interface {
+	// HandleEvent handles the given event within the given context and returns any error.
+	HandleEvent(ctx context.Context, e Event) error
+}
+
+// ErrorListenerFunc is a function that implements both the Listener and ErrorListener interfaces.
+type ErrorListenerFunc func(context.Context, Event) error
+
+// Handle implements the Listener interface for ErrorListenerFunc using a background context.
+func (f ErrorListenerFunc) Handle(e Event) bool {
+	return f(context.Background(), e) == nil
+}
+
+// HandleEvent implements the ErrorListener interface for ErrorListenerFunc.
+func (f ErrorListenerFunc) HandleEvent(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
@@ -0,0 +1,158 @@
+package event
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"maps"
+	"sync/atomic"
+	"time"
+)
+
+// Metadata describes an event occurrence for tracing a business flow across listeners.
+type Metadata struct {
+	// ID uniquely identifies the event occurrence.
+	ID string
+
+	// OccurredAt is the time the event was created.
+	OccurredAt time.Time
+
+	// CorrelationID is shared by every event of the same business flow.
+	// It defaults to the ID of the first event of the flow.
+	CorrelationID string
+
+	// CausationID is the ID of the event whose handling emitted this event.
+	CausationID string
+
+	// Headers holds free-form values such as tenant or user identifiers.
+	//
+	// The map is not synchronized: set headers before dispatching the event, or use the Header
+	// and SetHeader methods of BaseEvent once the event may be handled concurrently.
+	Headers map[string]string
+
+	// attempt is the number of the current call of a retried listener, accessed atomically.
+	attempt int32
+}
+
+// Attempt returns the number, counted from one, of the current call of the listener registered
+// with WithRetry that is handling the event, and zero while no such listener is.
+//
+// Plain listeners read it here. The number is shared by every dispatch of the event, so while the
+// same event is dispatched concurrently it may be the number of another dispatch's call; listeners
+// receiving a context can use AttemptFromContext instead, which is exact.
+func (m *Metadata) Attempt() int {
+	return int(atomic.LoadInt32(&m.attempt))
+}
+
+// MetadataEvent is implemented by events that carry metadata.
+// BaseEvent implements it, so custom events embedding *BaseEvent do too.
+//
+// The dispatcher links the metadata of an event on its first dispatch. Events not embedding
+// *BaseEvent are linked on every dispatch, so they must not be dispatched concurrently.
+type MetadataEvent interface {
+	Event
+
+	// Metadata returns the metadata of the event.
+	Metadata() *Metadata
+}
+
+// newEventID returns a random version 4 UUID.
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// currentEventKey is the context key for the event being dispatched.
+type currentEventKey struct{}
+
+// EventFromContext returns the event being dispatched in the given context.
+// Listeners receiving a context can use it to find the event that caused their work.
+func EventFromContext(ctx context.Context) (Event, bool) {
+	e, ok := ctx.Value(currentEventKey{}).(Event)
+	return e, ok
+}
+
+// withCurrentEvent records the event as being dispatched in the context, after linking
+// its metadata to the event that was being dispatched before.
+func withCurrentEvent(ctx context.Context, event Event) context.Context {
+	linkMetadata(ctx, event)
+	return context.WithValue(ctx, currentEventKey{}, event)
+}
+
+// linkMetadata links the metadata of the event to the event being dispatched in the context.
+//
+// An event dispatched while handling another event inherits its correlation ID and takes
+// its ID as causation ID. An event starting a flow is correlated with itself.
+func linkMetadata(ctx context.Context, event Event) {
+	me, ok := event.(MetadataEvent)
+	if !ok {
+		return
+	}
+
+	var parent *Metadata
+	if e, ok := EventFromContext(ctx); ok {
+		if pme, ok := e.(MetadataEvent); ok && pme != me {
+			parent = pme.Metadata()
+		}
+	}
+
+	// BaseEvent links its metadata once, so that an event can be dispatched concurrently
+	if l, ok := event.(metadataLinker); ok {
+		l.linkMetadata(parent)
+		return
+	}
+
+	link(me.Metadata(), parent)
+}
+
+// metadataSnapshotter is implemented by events that copy their metadata themselves, such as BaseEvent.
+type metadataSnapshotter interface {
+	snapshotMetadata() Metadata
+}
+
+// snapshotMetadata returns a copy of the metadata of the event that shares no map with it.
+func snapshotMetadata(me MetadataEvent) Metadata {
+	if s, ok := me.(metadataSnapshotter); ok {
+		return s.snapshotMetadata()
+	}
+
+	return me.Metadata().clone()
+}
+
+// clone returns a copy of the metadata with its own headers map, without the current attempt.
+func (m *Metadata) clone() Metadata {
+	return Metadata{
+		ID:            m.ID,
+		OccurredAt:    m.OccurredAt,
+		CorrelationID: m.CorrelationID,
+		CausationID:   m.CausationID,
+		Headers:       maps.Clone(m.Headers),
+	}
+}
+
+// metadataLinker is implemented by events that link their metadata themselves, such as BaseEvent.
+type metadataLinker interface {
+	linkMetadata(parent *Metadata)
+}
+
+// link fills in the correlation and causation IDs of the metadata that are not set yet.
+// The parent is the metadata of the event being handled, or nil.
+func link(md *Metadata, parent *Metadata) {
+	if parent != nil {
+		if md.CorrelationID == "" {
+			md.CorrelationID = parent.CorrelationID
+		}
+		if md.CausationID == "" {
+			md.CausationID = parent.ID
+		}
+	}
+
+	if md.CorrelationID == "" {
+		md.CorrelationID = md.ID
+	}
+}
@@ -0,0 +1,106 @@
+package event_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadata_Defaults(t *testing.T) {
+	e := event.NewEvent("user.created")
+	other := event.NewEvent("user.created")
+
+	md := e.Metadata()
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, md.ID)
+	assert.NotEqual(t, md.ID, other.Metadata().ID)
+	assert.False(t, md.OccurredAt.IsZero())
+	assert.NotNil(t, md.Headers)
+}
+
+func TestMetadata_CustomEventsImplementMetadataEvent(t *testing.T) {
+	var e event.Event = NewOrderCreatedEvent("ORD-1")
+
+	_, ok := e.(event.MetadataEvent)
+	assert.True(t, ok)
+}
+
+func TestMetadata_CorrelationPropagates(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	root := event.NewEvent("order.created")
+
+	var child, grandchild *event.BaseEvent
+	dispatcher.AddListener("order.created", event.ContextListenerFunc(func(ctx context.Context, e event.Event) bool {
+		child = event.NewEvent("payment.requested")
+		_, _ = dispatcher.DispatchContext(ctx, child)
+		return true
+	}))
+	dispatcher.AddListener("payment.requested", event.ContextListenerFunc(func(ctx context.Context, e event.Event) bool {
+		current, ok := event.EventFromContext(ctx)
+		require.True(t, ok)
+		assert.Same(t, child, current)
+
+		grandchild = event.NewEvent("payment.captured")
+		_, _ = dispatcher.DispatchContext(ctx, grandchild)
+		return true
+	}))
+
+	dispatcher.Dispatch(root)
+
+	rootID := root.Metadata().ID
+	assert.Equal(t, rootID, root.Metadata().CorrelationID)
+	assert.Empty(t, root.Metadata().CausationID)
+
+	require.NotNil(t, child)
+	assert.Equal(t, rootID, child.Metadata().CorrelationID)
+	assert.Equal(t, rootID, child.Metadata().CausationID)
+
+	require.NotNil(t, grandchild)
+	assert.Equal(t, rootID, grandchild.Metadata().CorrelationID)
+	assert.Equal(t, child.Metadata().ID, grandchild.Metadata().CausationID)
+}
+
+func TestMetadata_ExplicitCorrelationIsKept(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	e := event.NewEvent("order.created")
+	e.Metadata().CorrelationID = "checkout-123"
+	e.Metadata().Headers["tenant"] = "acme"
+
+	dispatcher.Dispatch(e)
+
+	assert.Equal(t, "checkout-123", e.Metadata().CorrelationID)
+	assert.Equal(t, "acme", e.Metadata().Headers["tenant"])
+}
+
+func TestMetadata_ConcurrentDispatch(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	e := event.NewEvent("order.created")
+	dispatcher.AddListener("order.created", event.ContextListenerFunc(func(ctx context.Context, received event.Event) bool {
+		md := received.(event.MetadataEvent).Metadata()
+		_ = md.CorrelationID + md.CausationID
+
+		e.SetHeader("handled", "yes")
+		_, _ = e.Header("tenant")
+		return true
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = dispatcher.DispatchContext(context.Background(), e)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, e.Metadata().ID, e.Metadata().CorrelationID)
+	value, ok := e.Header("handled")
+	assert.True(t, ok)
+	assert.Equal(t, "yes", value)
+}
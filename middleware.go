@@ -0,0 +1,129 @@
+package event
+
+import (
+	"context"
+	"slices"
+)
+
+// Handler handles an event within a context and reports any error.
+type Handler func(ctx context.Context, e Event) error
+
+// Middleware wraps a Handler with behavior that runs before and after it.
+//
+// Middleware registered with Use wraps a whole dispatch; the wrapped handler calls every listener
+// and returns their joined errors. Middleware registered with UseListener wraps each single listener
+// call. In both cases the code after next runs even when a listener stops propagation, fails or
+// panics, because panics are recovered inside the chain and only re-raised once it has returned.
+type Middleware func(next Handler) Handler
+
+// middlewareEntry is a middleware registered for every event or for a pattern of event names.
+type middlewareEntry struct {
+	// pattern is the event name or pattern the middleware applies to, or empty for every event.
+	pattern    string
+	middleware Middleware
+}
+
+// matches reports whether the middleware applies to the event name.
+func (m middlewareEntry) matches(eventName string) bool {
+	return m.pattern == "" || m.pattern == eventName || MatchPattern(m.pattern, eventName)
+}
+
+// Use adds middleware that wraps every dispatch. Middleware added first runs outermost.
+func (d *EventDispatcher) Use(middleware ...Middleware) {
+	d.UseFor("", middleware...)
+}
+
+// UseFor adds middleware that wraps the dispatch of events matching the event name or pattern.
+func (d *EventDispatcher) UseFor(eventName string, middleware ...Middleware) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.update(func(r *registry) {
+		r.dispatchMiddleware = slices.Clip(r.dispatchMiddleware)
+		for _, m := range middleware {
+			r.dispatchMiddleware = append(r.dispatchMiddleware, middlewareEntry{pattern: eventName, middleware: m})
+		}
+	})
+}
+
+// UseListener adds middleware that wraps every listener call. Middleware added first runs outermost.
+func (d *EventDispatcher) UseListener(middleware ...Middleware) {
+	d.UseListenerFor("", middleware...)
+}
+
+// UseListenerFor adds middleware that wraps each listener call for events matching the event name or pattern.
+func (d *EventDispatcher) UseListenerFor(eventName string, middleware ...Middleware) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.update(func(r *registry) {
+		r.listenerMiddleware = slices.Clip(r.listenerMiddleware)
+		for _, m := range middleware {
+			r.listenerMiddleware = append(r.listenerMiddleware, middlewareEntry{pattern: eventName, middleware: m})
+		}
+	})
+}
+
+// middlewareFor returns the dispatch and listener middleware that apply to the event name.
+func (r *registry) middlewareFor(eventName string) (dispatch, listener []Middleware) {
+	for _, entry := range r.dispatchMiddleware {
+		if entry.matches(eventName) {
+			dispatch = append(dispatch, entry.middleware)
+		}
+	}
+
+	for _, entry := range r.listenerMiddleware {
+		if entry.matches(eventName) {
+			listener = append(listener, entry.middleware)
+		}
+	}
+
+	return dispatch, listener
+}
+
+// hasMiddlewareFor reports whether any dispatch or listener middleware applies to the event name.
+func (r *registry) hasMiddlewareFor(eventName string) bool {
+	for _, entries := range [][]middlewareEntry{r.dispatchMiddleware, r.listenerMiddleware} {
+		for _, entry := range entries {
+			if entry.matches(eventName) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// chain wraps the handler with the middleware, the first middleware being outermost.
+func chain(handler Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
+
+// ListenerInfo describes the listener a listener middleware is wrapping.
+type ListenerInfo struct {
+	// ID identifies the listener registration.
+	ID ListenerID
+
+	// Name is the name given with WithName, if any.
+	Name string
+
+	// Listener is the registered listener.
+	Listener Listener
+
+	// Priority is the priority the listener was registered with.
+	Priority int
+}
+
+// listenerInfoKey is the context key for the ListenerInfo of the current listener call.
+type listenerInfoKey struct{}
+
+// ListenerInfoFromContext returns the listener being called.
+// It is available to listener middleware and to the listeners they wrap.
+func ListenerInfoFromContext(ctx context.Context) (ListenerInfo, bool) {
+	info, ok := ctx.Value(listenerInfoKey{}).(ListenerInfo)
+	return info, ok
+}
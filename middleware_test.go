@@ -0,0 +1,158 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(calls *[]string, name string) event.Middleware {
+	return func(next event.Handler) event.Handler {
+		return func(ctx context.Context, e event.Event) error {
+			*calls = append(*calls, name+":before")
+			err := next(ctx, e)
+			*calls = append(*calls, name+":after")
+			return err
+		}
+	}
+}
+
+func TestMiddleware_WrapsDispatch(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls []string
+	dispatcher.Use(recordingMiddleware(&calls, "outer"), recordingMiddleware(&calls, "inner"))
+
+	dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+		calls = append(calls, "listener1")
+		e.StopPropagation()
+		return true
+	}), 10)
+	dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+		calls = append(calls, "listener2")
+		return true
+	}))
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.Equal(t, []string{"outer:before", "inner:before", "listener1", "inner:after", "outer:after"}, calls)
+}
+
+func TestMiddleware_WrapsEachListener(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls []string
+	dispatcher.UseListener(recordingMiddleware(&calls, "mw"))
+
+	dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+		calls = append(calls, "listener1")
+		return true
+	}), 10)
+	dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+		calls = append(calls, "listener2")
+		return true
+	}))
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.Equal(t, []string{"mw:before", "listener1", "mw:after", "mw:before", "listener2", "mw:after"}, calls)
+}
+
+func TestMiddleware_PerEventName(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls []string
+	dispatcher.UseFor("order.*", recordingMiddleware(&calls, "order"))
+	dispatcher.UseListenerFor("user.created", recordingMiddleware(&calls, "user"))
+	dispatcher.AddListener("**", &TestListener{})
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	dispatcher.Dispatch(event.NewEvent("user.deleted"))
+
+	assert.Equal(t, []string{"order:before", "order:after", "user:before", "user:after"}, calls)
+}
+
+func TestMiddleware_SeesListenerErrors(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	errBoom := errors.New("boom")
+
+	var seen error
+	dispatcher.Use(func(next event.Handler) event.Handler {
+		return func(ctx context.Context, e event.Event) error {
+			seen = next(ctx, e)
+			return seen
+		}
+	})
+
+	dispatcher.AddListener("user.created", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		return errBoom
+	}))
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("user.created"))
+
+	assert.ErrorIs(t, seen, errBoom)
+	assert.NoError(t, result.MiddlewareErr)
+}
+
+func TestMiddleware_Error(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	errDenied := errors.New("denied")
+
+	dispatcher.Use(func(next event.Handler) event.Handler {
+		return func(ctx context.Context, e event.Event) error {
+			return errDenied
+		}
+	})
+
+	listener := &TestListener{}
+	dispatcher.AddListener("user.created", listener)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("user.created"))
+
+	assert.False(t, listener.called)
+	assert.ErrorIs(t, result.MiddlewareErr, errDenied)
+	assert.ErrorIs(t, result.Err(), errDenied)
+}
+
+func TestMiddleware_AfterRunsOnPanic(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls []string
+	dispatcher.Use(recordingMiddleware(&calls, "dispatch"))
+	dispatcher.UseListener(recordingMiddleware(&calls, "listener"))
+	dispatcher.AddListener("user.created", panickingListener("boom"))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		dispatcher.Dispatch(event.NewEvent("user.created"))
+	})
+
+	assert.Equal(t, []string{"dispatch:before", "listener:before", "listener:after", "dispatch:after"}, calls)
+}
+
+func TestMiddleware_ListenerInfo(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var infos []event.ListenerInfo
+	dispatcher.UseListener(func(next event.Handler) event.Handler {
+		return func(ctx context.Context, e event.Event) error {
+			info, ok := event.ListenerInfoFromContext(ctx)
+			require.True(t, ok)
+			infos = append(infos, info)
+			return next(ctx, e)
+		}
+	})
+
+	listener := &TestListener{}
+	dispatcher.AddListener("user.created", listener, 42)
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	require.Len(t, infos, 1)
+	assert.Equal(t, listener, infos[0].Listener)
+	assert.Equal(t, 42, infos[0].Priority)
+	assert.NotZero(t, infos[0].ID)
+}
@@ -0,0 +1,241 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrOrderCycle is reported when the Before and After constraints of listeners contradict each other.
+var ErrOrderCycle = errors.New("event: listener ordering constraints form a cycle")
+
+// OrderCycleError describes listeners whose Before and After constraints cannot all be satisfied.
+type OrderCycleError struct {
+	// EventName is the event whose listeners cannot be ordered.
+	EventName string
+
+	// Listeners are the names of the listeners that are left waiting on each other.
+	Listeners []string
+}
+
+// Error implements the error interface.
+func (e *OrderCycleError) Error() string {
+	return fmt.Sprintf("event: ordering constraints for %q form a cycle between listeners %s",
+		e.EventName, strings.Join(e.Listeners, ", "))
+}
+
+// Unwrap returns ErrOrderCycle.
+func (e *OrderCycleError) Unwrap() error {
+	return ErrOrderCycle
+}
+
+// WithName names the listener, so that other listeners can be ordered relative to it with
+// Before and After. Several listeners may share a name; constraints then apply to each of them.
+func WithName(name string) ListenerOption {
+	return func(o *listenerOptions) {
+		o.name = name
+	}
+}
+
+// Before makes the listener run before the listeners with the given names, whatever their priorities.
+// Names that no listener of the event has are ignored.
+//
+// Listen reports an *OrderCycleError, and registers nothing, if the constraints contradict those
+// already registered.
+func Before(names ...string) ListenerOption {
+	return func(o *listenerOptions) {
+		o.before = append(o.before, names...)
+	}
+}
+
+// After makes the listener run after the listeners with the given names, whatever their priorities.
+// Names that no listener of the event has are ignored.
+//
+// Listen reports an *OrderCycleError, and registers nothing, if the constraints contradict those
+// already registered.
+func After(names ...string) ListenerOption {
+	return func(o *listenerOptions) {
+		o.after = append(o.after, names...)
+	}
+}
+
+// Listeners returns the listeners that a dispatch of the named event calls, in the order they run.
+//
+// It includes the listeners registered for patterns matching the name, but not those registered
+// for event types with On, as they depend on the dispatched event rather than its name.
+func (d *EventDispatcher) Listeners(eventName string) []ListenerInfo {
+	listeners := d.registry.Load().listenersFor(eventName, nil)
+
+	infos := make([]ListenerInfo, len(listeners))
+	for i, l := range listeners {
+		infos[i] = l.info()
+	}
+
+	return infos
+}
+
+// info describes the listener registration.
+func (l ListenerPriority) info() ListenerInfo {
+	return ListenerInfo{ID: l.id, Name: l.name, Listener: l.Listener, Priority: l.Priority}
+}
+
+// constrained reports whether the listener has Before or After constraints.
+func (l ListenerPriority) constrained() bool {
+	return len(l.before) > 0 || len(l.after) > 0
+}
+
+// checkOrder reports an *OrderCycleError if adding the listener for the event name or pattern
+// would give an event listeners with contradicting constraints. The caller must hold the write lock.
+func (d *EventDispatcher) checkOrder(eventName string, l ListenerPriority) error {
+	// Only a named or constrained listener can close a cycle
+	if l.name == "" && !l.constrained() {
+		return nil
+	}
+
+	reg := d.registry.Load()
+
+	// A pattern listener runs with the listeners of every event name it matches
+	eventNames := []string{eventName}
+	if IsPattern(eventName) {
+		for name := range reg.listeners {
+			if !IsPattern(name) && MatchPattern(eventName, name) {
+				eventNames = append(eventNames, name)
+			}
+		}
+	}
+
+	for _, name := range eventNames {
+		listeners := append(slices.Clip(reg.listenersFor(name, nil)), l)
+		if _, err := orderListeners(listeners); err != nil {
+			var cycleErr *OrderCycleError
+			if errors.As(err, &cycleErr) {
+				cycleErr.EventName = name
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orderListeners sorts the listeners by priority and registration order, then applies their Before
+// and After constraints. The listeners are sorted in place.
+//
+// A listener that must run before others is moved up to run as early as the highest priority among
+// them requires, and the listeners without constraints keep their places.
+//
+// If the constraints contradict each other, the listeners are still all returned, with the cycle
+// broken in priority order, together with an *OrderCycleError.
+func orderListeners(listeners EventListeners) (EventListeners, error) {
+	sort.Sort(listeners)
+
+	if !slices.ContainsFunc(listeners, ListenerPriority.constrained) {
+		return listeners, nil
+	}
+
+	byName := make(map[string][]int)
+	for i, l := range listeners {
+		if l.name != "" {
+			byName[l.name] = append(byName[l.name], i)
+		}
+	}
+
+	// successors lists the listeners that must wait for each listener,
+	// and waiting counts the listeners each listener must wait for
+	successors := make([][]int, len(listeners))
+	waiting := make([]int, len(listeners))
+	for i, l := range listeners {
+		for _, name := range l.before {
+			for _, j := range byName[name] {
+				if i != j {
+					successors[i] = append(successors[i], j)
+					waiting[j]++
+				}
+			}
+		}
+		for _, name := range l.after {
+			for _, j := range byName[name] {
+				if i != j {
+					successors[j] = append(successors[j], i)
+					waiting[i]++
+				}
+			}
+		}
+	}
+
+	// A listener runs with the highest priority of the listeners waiting for it
+	priorities := make([]int, len(listeners))
+	for i, l := range listeners {
+		priorities[i] = l.Priority
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := range listeners {
+			for _, j := range successors[i] {
+				if priorities[j] > priorities[i] {
+					priorities[i] = priorities[j]
+					changed = true
+				}
+			}
+		}
+	}
+
+	ordered := make(EventListeners, 0, len(listeners))
+	done := make([]bool, len(listeners))
+	var cycleErr *OrderCycleError
+
+	for len(ordered) < len(listeners) {
+		next := nextListener(priorities, done, waiting, false)
+
+		// Every remaining listener waits for another, so break the cycle in priority order
+		if next < 0 {
+			if cycleErr == nil {
+				cycleErr = &OrderCycleError{Listeners: waitingNames(listeners, done, waiting)}
+			}
+			next = nextListener(priorities, done, waiting, true)
+		}
+
+		done[next] = true
+		ordered = append(ordered, listeners[next])
+		for _, j := range successors[next] {
+			waiting[j]--
+		}
+	}
+
+	if cycleErr != nil {
+		return ordered, cycleErr
+	}
+
+	return ordered, nil
+}
+
+// nextListener returns the index of the remaining listener with the highest priority, the earliest
+// registered first, among those waiting for no other unless waiting ones are allowed, or -1 if none.
+func nextListener(priorities []int, done []bool, waiting []int, allowWaiting bool) int {
+	next := -1
+	for i := range priorities {
+		if done[i] || (waiting[i] > 0 && !allowWaiting) {
+			continue
+		}
+		if next < 0 || priorities[i] > priorities[next] {
+			next = i
+		}
+	}
+
+	return next
+}
+
+// waitingNames returns the sorted, distinct names of the remaining listeners that wait for others.
+func waitingNames(listeners EventListeners, done []bool, waiting []int) []string {
+	var names []string
+	for i, l := range listeners {
+		if !done[i] && waiting[i] > 0 && l.name != "" && !slices.Contains(names, l.name) {
+			names = append(names, l.name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,187 @@
+package event_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder returns a listener appending its name to calls.
+func recorder(calls *[]string, name string) event.Listener {
+	return event.ListenerFunc(func(e event.Event) bool {
+		*calls = append(*calls, name)
+		return true
+	})
+}
+
+func TestOrder_BeforeAndAfter(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var calls []string
+
+	_, err := dispatcher.Listen("order.created", recorder(&calls, "audit"), event.WithName("audit"), event.WithPriority(100))
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("order.created", recorder(&calls, "notify"), event.WithName("notify"), event.After("audit"))
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("order.created", recorder(&calls, "validation"), event.WithName("validation"), event.Before("audit"))
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("order.created", recorder(&calls, "other"), event.WithPriority(50))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+
+	assert.Equal(t, []string{"validation", "audit", "other", "notify"}, calls)
+}
+
+func TestOrder_ConstraintOnUnknownNameIsIgnored(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var calls []string
+
+	_, err := dispatcher.Listen("order.created", recorder(&calls, "first"), event.After("missing"), event.WithPriority(10))
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("order.created", recorder(&calls, "second"))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
+
+func TestOrder_CycleIsRejected(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var calls []string
+
+	_, err := dispatcher.Listen("order.created", recorder(&calls, "a"), event.WithName("a"), event.Before("b"))
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("order.created", recorder(&calls, "b"), event.WithName("b"), event.Before("c"))
+	require.NoError(t, err)
+
+	sub, err := dispatcher.Listen("order.created", recorder(&calls, "c"), event.WithName("c"), event.Before("a"))
+
+	assert.Nil(t, sub)
+	assert.ErrorIs(t, err, event.ErrOrderCycle)
+
+	var cycleErr *event.OrderCycleError
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, "order.created", cycleErr.EventName)
+	assert.Equal(t, []string{"a", "b", "c"}, cycleErr.Listeners)
+
+	// The rejected listener was not registered
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+	assert.Equal(t, []string{"a", "b"}, calls)
+}
+
+func TestOrder_CycleWithPatternIsRejected(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	_, err := dispatcher.Listen("order.created", recorder(new([]string), "audit"), event.WithName("audit"), event.Before("log"))
+	require.NoError(t, err)
+
+	_, err = dispatcher.Listen("order.*", recorder(new([]string), "log"), event.WithName("log"), event.Before("audit"))
+
+	var cycleErr *event.OrderCycleError
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, "order.created", cycleErr.EventName)
+}
+
+func TestOrder_ConstraintsAcrossPatterns(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var calls []string
+
+	_, err := dispatcher.Listen("order.**", recorder(&calls, "log"), event.WithName("log"), event.After("validation"), event.WithPriority(100))
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("order.created", recorder(&calls, "validation"), event.WithName("validation"))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+
+	assert.Equal(t, []string{"validation", "log"}, calls)
+}
+
+func TestOrder_RemovingListenerDropsItsConstraints(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var calls []string
+
+	_, err := dispatcher.Listen("order.created", recorder(&calls, "audit"), event.WithName("audit"), event.WithPriority(10))
+	require.NoError(t, err)
+	sub, err := dispatcher.Listen("order.created", recorder(&calls, "first"), event.Before("audit"))
+	require.NoError(t, err)
+
+	sub.Unsubscribe()
+
+	// A cycle with the removed listener is no longer possible
+	_, err = dispatcher.Listen("order.created", recorder(&calls, "late"), event.WithName("late"), event.After("audit"))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+	assert.Equal(t, []string{"audit", "late"}, calls)
+}
+
+func TestOrder_Listeners(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	_, err := dispatcher.Listen("order.created", recorder(new([]string), "audit"), event.WithName("audit"), event.WithPriority(100))
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("order.*", recorder(new([]string), "validation"), event.WithName("validation"), event.Before("audit"))
+	require.NoError(t, err)
+	dispatcher.AddListener("order.created", recorder(new([]string), "unnamed"), 50)
+
+	listeners := dispatcher.Listeners("order.created")
+
+	require.Len(t, listeners, 3)
+	assert.Equal(t, "validation", listeners[0].Name)
+	assert.Equal(t, "audit", listeners[1].Name)
+	assert.Equal(t, 100, listeners[1].Priority)
+	assert.Equal(t, "", listeners[2].Name)
+	assert.Equal(t, 50, listeners[2].Priority)
+
+	assert.Empty(t, dispatcher.Listeners("user.created"))
+}
+
+type orderedSubscriber struct {
+	calls []string
+}
+
+func (s *orderedSubscriber) Validate(e event.Event) {
+	s.calls = append(s.calls, "validate")
+}
+
+func (s *orderedSubscriber) Save(e event.Event) {
+	s.calls = append(s.calls, "save")
+}
+
+func (s *orderedSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return map[string][]event.SubscriberConfig{
+		"order.created": {
+			{Method: "Save", Name: "save", Priority: 10},
+			{Method: "Validate", Name: "validate", Before: []string{"save"}},
+		},
+	}
+}
+
+func TestOrder_SubscriberConstraints(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &orderedSubscriber{}
+
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+
+	assert.Equal(t, []string{"validate", "save"}, subscriber.calls)
+}
+
+func TestOrder_SubscriberCycleRegistersNothing(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	_, err := dispatcher.Listen("order.created", recorder(new([]string), "save"), event.WithName("save"), event.Before("validate"))
+	require.NoError(t, err)
+
+	_, err = event.RegisterSubscriber(dispatcher, &orderedSubscriber{})
+
+	var subErr *event.SubscriberError
+	require.True(t, errors.As(err, &subErr))
+	assert.ErrorIs(t, err, event.ErrOrderCycle)
+	assert.Len(t, dispatcher.Listeners("order.created"), 1)
+}
@@ -0,0 +1,117 @@
+package event
+
+import "strings"
+
+const (
+	// segmentSeparator separates the segments of dotted event names.
+	segmentSeparator = "."
+
+	// singleWildcard matches exactly one segment of an event name.
+	singleWildcard = "*"
+
+	// multiWildcard matches zero or more segments of an event name.
+	multiWildcard = "**"
+)
+
+// IsPattern reports whether the event name contains a wildcard segment.
+//
+// Event names are split into segments on dots. A "*" segment matches exactly one segment
+// and a "**" segment matches zero or more segments, so "user.*" matches "user.created",
+// "*.created" matches "order.created" and "order.**" matches "order" and "order.item.added".
+func IsPattern(name string) bool {
+	for _, segment := range strings.Split(name, segmentSeparator) {
+		if segment == singleWildcard || segment == multiWildcard {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchPattern reports whether the event name matches the pattern.
+func MatchPattern(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, segmentSeparator), strings.Split(name, segmentSeparator))
+}
+
+// matchSegments matches name segments against pattern segments.
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	switch pattern[0] {
+	case multiWildcard:
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	case singleWildcard:
+		return len(name) > 0 && matchSegments(pattern[1:], name[1:])
+	default:
+		return len(name) > 0 && pattern[0] == name[0] && matchSegments(pattern[1:], name[1:])
+	}
+}
+
+// patternNode is a node in a trie of event name patterns, keyed by segment.
+type patternNode struct {
+	children map[string]*patternNode
+
+	// pattern is the full pattern ending at this node, or empty if none does.
+	pattern string
+}
+
+// newPatternNode creates an empty trie node.
+func newPatternNode() *patternNode {
+	return &patternNode{children: make(map[string]*patternNode)}
+}
+
+// insert adds a pattern to the trie.
+func (n *patternNode) insert(pattern string) {
+	node := n
+	for _, segment := range strings.Split(pattern, segmentSeparator) {
+		child, ok := node.children[segment]
+		if !ok {
+			child = newPatternNode()
+			node.children[segment] = child
+		}
+		node = child
+	}
+	node.pattern = pattern
+}
+
+// match calls fn with every pattern in the trie that matches the event name.
+// A pattern may be reported more than once when it contains several "**" segments.
+func (n *patternNode) match(name string, fn func(pattern string)) {
+	n.matchSegments(strings.Split(name, segmentSeparator), fn)
+}
+
+// matchSegments walks the trie with the remaining name segments.
+func (n *patternNode) matchSegments(segments []string, fn func(pattern string)) {
+	if len(segments) == 0 && n.pattern != "" {
+		fn(n.pattern)
+	}
+
+	if child, ok := n.children[multiWildcard]; ok {
+		for i := 0; i <= len(segments); i++ {
+			child.matchSegments(segments[i:], fn)
+		}
+	}
+
+	if len(segments) == 0 {
+		return
+	}
+
+	if child, ok := n.children[singleWildcard]; ok {
+		child.matchSegments(segments[1:], fn)
+	}
+
+	if segments[0] == singleWildcard || segments[0] == multiWildcard {
+		return
+	}
+
+	if child, ok := n.children[segments[0]]; ok {
+		child.matchSegments(segments[1:], fn)
+	}
+}
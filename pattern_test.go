@@ -0,0 +1,129 @@
+package event_test
+
+import (
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"user.created", "user.created", true},
+		{"user.*", "user.created", true},
+		{"user.*", "user", false},
+		{"user.*", "user.profile.updated", false},
+		{"*.created", "order.created", true},
+		{"*.created", "order.updated", false},
+		{"order.**", "order", true},
+		{"order.**", "order.created", true},
+		{"order.**", "order.item.added", true},
+		{"order.**", "user.created", false},
+		{"**.added", "order.item.added", true},
+		{"**", "anything.at.all", true},
+		{"a.**.z", "a.z", true},
+		{"a.**.z", "a.b.c.z", true},
+		{"a.**.z", "a.b.c", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, event.MatchPattern(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestIsPattern(t *testing.T) {
+	assert.True(t, event.IsPattern("user.*"))
+	assert.True(t, event.IsPattern("order.**"))
+	assert.False(t, event.IsPattern("user.created"))
+	assert.False(t, event.IsPattern("user.cre*ted"))
+}
+
+func TestDispatcher_PatternListeners(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls []string
+	record := func(name string) event.Listener {
+		return event.ListenerFunc(func(e event.Event) bool {
+			calls = append(calls, name)
+			return true
+		})
+	}
+
+	dispatcher.AddListener("user.created", record("exact"), 10)
+	dispatcher.AddListener("user.*", record("user.*"), 20)
+	dispatcher.AddListener("*.created", record("*.created"), 10)
+	dispatcher.AddListener("order.**", record("order.**"), 0)
+	dispatcher.AddListener("**", record("**"), -10)
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	assert.Equal(t, []string{"user.*", "exact", "*.created", "**"}, calls)
+
+	calls = nil
+	dispatcher.Dispatch(event.NewEvent("order.item.added"))
+	assert.Equal(t, []string{"order.**", "**"}, calls)
+}
+
+func TestDispatcher_PatternStopPropagation(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	dispatcher.AddListener("user.*", event.ListenerFunc(func(e event.Event) bool {
+		e.StopPropagation()
+		return true
+	}), 100)
+
+	listener := &TestListener{}
+	dispatcher.AddListener("user.created", listener)
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.False(t, listener.called)
+}
+
+func TestDispatcher_RemovePatternListener(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	listener := &TestListener{}
+
+	dispatcher.AddListener("user.*", listener)
+	assert.True(t, dispatcher.HasListener("user.*", listener))
+	assert.False(t, dispatcher.HasListener("user.created", listener))
+
+	dispatcher.RemoveListener("user.*", listener)
+	assert.False(t, dispatcher.HasListener("user.*", listener))
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	assert.False(t, listener.called)
+}
+
+type wildcardSubscriber struct {
+	received []string
+}
+
+func (s *wildcardSubscriber) OnUserEvent(e event.Event) bool {
+	s.received = append(s.received, e.Name())
+	return true
+}
+
+func (s *wildcardSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return map[string][]event.SubscriberConfig{
+		"user.*": {{Method: "OnUserEvent"}},
+	}
+}
+
+func TestSubscriber_PatternEvents(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &wildcardSubscriber{}
+
+	event.RegisterSubscriber(dispatcher, subscriber)
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	dispatcher.Dispatch(event.NewEvent("user.deleted"))
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+
+	assert.Equal(t, []string{"user.created", "user.deleted"}, subscriber.received)
+}
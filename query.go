@@ -0,0 +1,107 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNoAnswer is returned by AskFirst when no listener replied to the query.
+var ErrNoAnswer = errors.New("event: no listener answered the query")
+
+// Query is an event that collects typed replies from its listeners.
+//
+// Listeners answer a query by calling Reply, which is safe for concurrent use, instead of writing
+// into its arguments. They are registered like any other listener, for example with
+// TypedContextListener on *Query[R], and are called in priority order. Custom queries can embed
+// *Query[R] to carry typed fields.
+type Query[R any] struct {
+	*BaseEvent
+
+	mu      sync.Mutex
+	replies []R
+	first   bool
+}
+
+// NewQuery creates a new query with the given name and optional arguments.
+func NewQuery[R any](name string, arguments ...map[string]interface{}) *Query[R] {
+	return &Query[R]{BaseEvent: NewEvent(name, arguments...)}
+}
+
+// Reply records an answer to the query. When the query is asked with AskFirst,
+// the first reply stops the propagation to the remaining listeners.
+func (q *Query[R]) Reply(reply R) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.replies = append(q.replies, reply)
+	if q.first {
+		q.StopPropagation()
+	}
+}
+
+// Replies returns a copy of the replies recorded so far, in the order they were given.
+func (q *Query[R]) Replies() []R {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	replies := make([]R, len(q.replies))
+	copy(replies, q.replies)
+
+	return replies
+}
+
+// query implements the QueryEvent interface.
+func (q *Query[R]) query() *Query[R] {
+	return q
+}
+
+// QueryEvent is implemented by *Query[R] and by the types embedding it,
+// which lets custom queries be asked with AskFirst, AskAll and AskReduce.
+type QueryEvent[R any] interface {
+	Event
+	query() *Query[R]
+}
+
+// AskFirst dispatches the query and returns the first reply, given by the listener with the highest
+// priority that answers. The remaining listeners are not called once it has replied.
+//
+// If no listener replies, the error wraps ErrNoAnswer together with the errors of the dispatch,
+// such as the cause of the context's cancellation when its deadline passed first.
+func AskFirst[R any](ctx context.Context, d *EventDispatcher, q QueryEvent[R]) (R, error) {
+	query := q.query()
+	query.mu.Lock()
+	query.first = true
+	query.mu.Unlock()
+
+	result := d.DispatchWithResult(ctx, q)
+
+	replies := query.Replies()
+	if len(replies) == 0 {
+		var zero R
+		return zero, errors.Join(ErrNoAnswer, result.Err())
+	}
+
+	return replies[0], nil
+}
+
+// AskAll dispatches the query and returns the replies of every listener, in priority order,
+// together with the errors of the dispatch. Once the context is done, the remaining listeners
+// are not called and the replies given so far are returned with the cause of the cancellation.
+func AskAll[R any](ctx context.Context, d *EventDispatcher, q QueryEvent[R]) ([]R, error) {
+	result := d.DispatchWithResult(ctx, q)
+	return q.query().Replies(), result.Err()
+}
+
+// AskReduce dispatches the query like AskAll and folds the replies, in priority order,
+// into a single value starting from initial.
+func AskReduce[R, A any](ctx context.Context, d *EventDispatcher, q QueryEvent[R], initial A, reduce func(A, R) A) (A, error) {
+	replies, err := AskAll(ctx, d, q)
+
+	acc := initial
+	for _, reply := range replies {
+		acc = reduce(acc, reply)
+	}
+
+	return acc, err
+}
@@ -0,0 +1,144 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Price struct {
+	Plugin string
+	Amount float64
+}
+
+// pricer returns a listener replying to price queries with the given price.
+func pricer(plugin string, amount float64) event.Listener {
+	return event.TypedContextListener(func(ctx context.Context, q *event.Query[Price]) error {
+		q.Reply(Price{Plugin: plugin, Amount: amount})
+		return nil
+	})
+}
+
+func TestQuery_AskAll(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("order.price", pricer("standard", 10), 0)
+	dispatcher.AddListener("order.price", pricer("premium", 25), 10)
+	dispatcher.AddListener("order.price", event.ListenerFunc(func(e event.Event) bool {
+		// Listeners may pass without replying
+		return true
+	}), 5)
+
+	replies, err := event.AskAll(context.Background(), dispatcher, event.NewQuery[Price]("order.price"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Price{{"premium", 25}, {"standard", 10}}, replies)
+}
+
+func TestQuery_AskFirst(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	called := false
+	dispatcher.AddListener("order.price", event.ListenerFunc(func(e event.Event) bool {
+		called = true
+		return true
+	}), -10)
+	dispatcher.AddListener("order.price", pricer("standard", 10), 0)
+	dispatcher.AddListener("order.price", pricer("premium", 25), 10)
+
+	price, err := event.AskFirst(context.Background(), dispatcher, event.NewQuery[Price]("order.price"))
+
+	require.NoError(t, err)
+	assert.Equal(t, Price{"premium", 25}, price)
+	assert.False(t, called)
+}
+
+func TestQuery_AskFirstWithoutAnswer(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	errUnavailable := errors.New("unavailable")
+
+	dispatcher.AddListener("order.price", event.TypedListener(func(q *event.Query[Price]) error {
+		return errUnavailable
+	}))
+
+	_, err := event.AskFirst(context.Background(), dispatcher, event.NewQuery[Price]("order.price"))
+
+	assert.ErrorIs(t, err, event.ErrNoAnswer)
+	assert.ErrorIs(t, err, errUnavailable)
+}
+
+func TestQuery_AskReduce(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("order.price", pricer("standard", 10))
+	dispatcher.AddListener("order.price", pricer("discount", 7))
+	dispatcher.AddListener("order.price", pricer("premium", 25))
+
+	cheapest, err := event.AskReduce(context.Background(), dispatcher, event.NewQuery[Price]("order.price"), Price{Amount: 1e9},
+		func(best, p Price) Price {
+			if p.Amount < best.Amount {
+				return p
+			}
+			return best
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, Price{"discount", 7}, cheapest)
+}
+
+func TestQuery_Timeout(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("order.price", pricer("fast", 10), 10)
+	dispatcher.AddListener("order.price", event.TypedContextListener(func(ctx context.Context, q *event.Query[Price]) error {
+		<-ctx.Done()
+		return nil
+	}), 5)
+	dispatcher.AddListener("order.price", pricer("late", 20), 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	replies, err := event.AskAll(ctx, dispatcher, event.NewQuery[Price]("order.price"))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []Price{{"fast", 10}}, replies)
+}
+
+type PriceQuery struct {
+	*event.Query[Price]
+	OrderID string
+}
+
+func TestQuery_CustomQuery(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("order.price", event.TypedListener(func(q *PriceQuery) error {
+		q.Reply(Price{Plugin: q.OrderID, Amount: 1})
+		return nil
+	}))
+
+	q := &PriceQuery{Query: event.NewQuery[Price]("order.price"), OrderID: "ORD-1"}
+	price, err := event.AskFirst(context.Background(), dispatcher, q)
+
+	require.NoError(t, err)
+	assert.Equal(t, Price{"ORD-1", 1}, price)
+}
+
+func TestQuery_ConcurrentReplies(t *testing.T) {
+	q := event.NewQuery[int]("count")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			q.Reply(i)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, q.Replies(), 50)
+}
@@ -0,0 +1,104 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// RecoveryPolicy controls what the dispatcher does when a listener panics.
+type RecoveryPolicy int
+
+const (
+	// PanicPropagate lets the panic reach the caller of Dispatch. This is the default.
+	// The remaining listeners are skipped and the panic is re-raised once the middleware has returned.
+	// An AsyncDispatcher has no caller to re-raise it on, so it returns the panic from Future.Err.
+	PanicPropagate RecoveryPolicy = iota
+
+	// PanicRecoverContinue recovers the panic, records it and continues with the next listener.
+	PanicRecoverContinue
+
+	// PanicRecoverStop recovers the panic, records it and skips the remaining listeners.
+	PanicRecoverStop
+)
+
+// PanicHandler is called with every panic recovered by the dispatcher.
+type PanicHandler func(*ListenerPanicError)
+
+// ListenerPanicError is the error recorded when a listener panics and the panic is recovered.
+type ListenerPanicError struct {
+	// EventName is the name of the event being dispatched.
+	EventName string
+
+	// Listener is the listener that panicked, or nil if a command handler panicked.
+	Listener Listener
+
+	// Handler is the command handler that panicked, or nil if a listener panicked.
+	Handler CommandHandler
+
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *ListenerPanicError) Error() string {
+	if e.Handler != nil {
+		return fmt.Sprintf("event: command handler %T panicked handling %q: %v", e.Handler, e.EventName, e.Value)
+	}
+
+	return fmt.Sprintf("event: listener %T panicked handling %q: %v", e.Listener, e.EventName, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *ListenerPanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// WithRecoveryPolicy sets what the dispatcher does when a listener panics.
+func WithRecoveryPolicy(policy RecoveryPolicy) DispatcherOption {
+	return func(d *EventDispatcher) {
+		d.recovery = policy
+	}
+}
+
+// WithPanicHandler sets a handler that is called with every recovered panic,
+// for example to report it to an error tracker.
+func WithPanicHandler(handler PanicHandler) DispatcherOption {
+	return func(d *EventDispatcher) {
+		d.panicHandler = handler
+	}
+}
+
+// call invokes the listener and converts a panic into a *ListenerPanicError.
+// Under PanicPropagate the panic is re-raised by DispatchWithResult once the middleware has returned.
+func (d *EventDispatcher) call(ctx context.Context, listener Listener, event Event) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = d.panicked(&ListenerPanicError{
+				EventName: event.Name(),
+				Listener:  listener,
+				Value:     v,
+				Stack:     debug.Stack(),
+			})
+		}
+	}()
+
+	return handle(ctx, listener, event)
+}
+
+// panicked passes a recovered panic to the panic handler, unless it is about to be re-raised,
+// and returns it as an error.
+func (d *EventDispatcher) panicked(panicErr *ListenerPanicError) error {
+	if d.panicHandler != nil && d.recovery != PanicPropagate {
+		d.panicHandler(panicErr)
+	}
+
+	return panicErr
+}
@@ -0,0 +1,81 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panickingListener(value interface{}) event.Listener {
+	return event.ListenerFunc(func(e event.Event) bool {
+		panic(value)
+	})
+}
+
+func TestRecovery_PropagateByDefault(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("test.event", panickingListener("boom"))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		dispatcher.Dispatch(event.NewEvent("test.event"))
+	})
+}
+
+func TestRecovery_RecoverContinue(t *testing.T) {
+	var reported []*event.ListenerPanicError
+	dispatcher := event.NewDispatcher(
+		event.WithRecoveryPolicy(event.PanicRecoverContinue),
+		event.WithPanicHandler(func(err *event.ListenerPanicError) {
+			reported = append(reported, err)
+		}),
+	)
+
+	dispatcher.AddListener("test.event", panickingListener("boom"), 100)
+	listener := &TestListener{}
+	dispatcher.AddListener("test.event", listener, 50)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("test.event"))
+
+	assert.True(t, listener.called)
+	require.Len(t, result.Listeners, 2)
+	assert.Equal(t, event.OutcomePanicked, result.Listeners[0].Outcome)
+	assert.Equal(t, event.OutcomeHandled, result.Listeners[1].Outcome)
+
+	var panicErr *event.ListenerPanicError
+	require.True(t, errors.As(result.Err(), &panicErr))
+	assert.Equal(t, "test.event", panicErr.EventName)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+
+	require.Len(t, reported, 1)
+	assert.Same(t, panicErr, reported[0])
+}
+
+func TestRecovery_RecoverStop(t *testing.T) {
+	dispatcher := event.NewDispatcher(event.WithRecoveryPolicy(event.PanicRecoverStop))
+
+	dispatcher.AddListener("test.event", panickingListener("boom"), 100)
+	listener := &TestListener{}
+	dispatcher.AddListener("test.event", listener, 50)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("test.event"))
+
+	assert.False(t, listener.called)
+	require.Len(t, result.Listeners, 2)
+	assert.Equal(t, event.OutcomePanicked, result.Listeners[0].Outcome)
+	assert.Equal(t, event.OutcomeSkipped, result.Listeners[1].Outcome)
+}
+
+func TestRecovery_PanicErrorUnwrap(t *testing.T) {
+	errBoom := errors.New("boom")
+	dispatcher := event.NewDispatcher(event.WithRecoveryPolicy(event.PanicRecoverContinue))
+	dispatcher.AddListener("test.event", panickingListener(errBoom))
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("test.event"))
+
+	assert.ErrorIs(t, result.Err(), errBoom)
+}
@@ -0,0 +1,206 @@
+package event
+
+import (
+	"maps"
+	"reflect"
+	"sort"
+	"sync/atomic"
+)
+
+// registry is a snapshot of the listeners and middleware of an EventDispatcher.
+//
+// Dispatches load the current registry and read it without locking. Writers hold the dispatcher's
+// lock and never modify a published map or slice; they publish a changed copy instead. The listeners
+// of an event name are kept in a listenerSet, so adding a listener to a known event name swaps that
+// set alone and leaves the registry in place.
+type registry struct {
+	// listeners maps event names and patterns to their listeners.
+	listeners map[string]*listenerSet
+
+	// patterns indexes the patterns in listeners, or is nil when there are none.
+	patterns *patternNode
+
+	// typed maps event types to the listeners registered with On.
+	typed map[reflect.Type]EventListeners
+
+	dispatchMiddleware []middlewareEntry
+	listenerMiddleware []middlewareEntry
+}
+
+// newRegistry creates an empty registry.
+func newRegistry() *registry {
+	return &registry{
+		listeners: make(map[string]*listenerSet),
+		typed:     make(map[reflect.Type]EventListeners),
+	}
+}
+
+// listenersFor returns the exact and pattern listeners for the event name and, unless eventType
+// is nil, the type listeners for the event type, merged into one list in the order they run.
+// The returned slice must not be modified.
+//
+// When only exact listeners apply, their snapshot is returned as is rather than merged into a copy.
+func (r *registry) listenersFor(eventName string, eventType reflect.Type) EventListeners {
+	var listeners EventListeners
+	if set, ok := r.listeners[eventName]; ok {
+		listeners = set.load()
+	}
+
+	var extra EventListeners
+	if r.patterns != nil {
+		r.patterns.match(eventName, func(pattern string) {
+			if pattern != eventName {
+				extra = append(extra, r.listeners[pattern].load()...)
+			}
+		})
+	}
+
+	if eventType != nil {
+		for registered, typeListeners := range r.typed {
+			if matchesType(registered, eventType) {
+				extra = append(extra, typeListeners...)
+			}
+		}
+	}
+
+	if len(extra) == 0 {
+		return listeners
+	}
+
+	merged := make(EventListeners, 0, len(listeners)+len(extra))
+	merged = append(merged, listeners...)
+	merged = append(merged, extra...)
+	sort.Sort(merged)
+
+	// A pattern matched more than once contributes duplicates, which are now adjacent
+	unique := merged[:1]
+	for _, l := range merged[1:] {
+		if l.id != unique[len(unique)-1].id {
+			unique = append(unique, l)
+		}
+	}
+
+	// Constraints are checked at registration against the event names known then,
+	// so a cycle showing only for a newer event name is broken in priority order
+	unique, _ = orderListeners(unique)
+
+	return unique
+}
+
+// listenerSet holds the listeners of one event name or pattern, ordered by priority and then
+// registration order. The slice is never modified; adding or removing a listener swaps it.
+type listenerSet struct {
+	snapshot atomic.Pointer[EventListeners]
+}
+
+// newListenerSet creates an empty listener set.
+func newListenerSet() *listenerSet {
+	s := &listenerSet{}
+	s.store(EventListeners{})
+
+	return s
+}
+
+// load returns the current listeners. The returned slice must not be modified.
+func (s *listenerSet) load() EventListeners {
+	return *s.snapshot.Load()
+}
+
+// store publishes the listeners, which must not be modified afterwards.
+func (s *listenerSet) store(listeners EventListeners) {
+	s.snapshot.Store(&listeners)
+}
+
+// update publishes a copy of the registry changed by fn. The caller must hold the write lock,
+// and fn must copy any map or slice of the registry it changes.
+func (d *EventDispatcher) update(fn func(r *registry)) {
+	next := *d.registry.Load()
+	fn(&next)
+	d.registry.Store(&next)
+}
+
+// listenerSet returns the listener set of the event name or pattern, publishing an empty one
+// if there is none yet. The caller must hold the write lock.
+func (d *EventDispatcher) listenerSet(eventName string) *listenerSet {
+	if set, ok := d.registry.Load().listeners[eventName]; ok {
+		return set
+	}
+
+	set := newListenerSet()
+	d.update(func(r *registry) {
+		r.listeners = maps.Clone(r.listeners)
+		r.listeners[eventName] = set
+
+		// Index patterns so that dispatch can find them by event name
+		if IsPattern(eventName) {
+			r.patterns = indexPatterns(r.listeners)
+		}
+	})
+
+	return set
+}
+
+// removeWhere removes the listeners of the specified event that match the predicate.
+// The caller must hold the write lock.
+func (d *EventDispatcher) removeWhere(eventName string, match func(ListenerPriority) bool) {
+	set, ok := d.registry.Load().listeners[eventName]
+	if !ok {
+		return
+	}
+
+	current := set.load()
+	kept := make(EventListeners, 0, len(current))
+	for _, registered := range current {
+		if !match(registered) {
+			kept = append(kept, registered)
+		} else if registered.limit != nil {
+			registered.limit.stop()
+		}
+	}
+
+	if len(kept) == len(current) {
+		return
+	}
+
+	// Ordering constraints of the removed listeners no longer apply
+	kept, _ = orderListeners(kept)
+	set.store(kept)
+
+	// Drop patterns without listeners so they are no longer matched
+	if len(kept) == 0 && IsPattern(eventName) {
+		d.update(func(r *registry) {
+			r.listeners = maps.Clone(r.listeners)
+			delete(r.listeners, eventName)
+			r.patterns = indexPatterns(r.listeners)
+		})
+	}
+}
+
+// updateTyped replaces the listeners of the event type with the result of fn.
+// The caller must hold the write lock.
+func (d *EventDispatcher) updateTyped(eventType reflect.Type, fn func(EventListeners) EventListeners) {
+	d.update(func(r *registry) {
+		r.typed = maps.Clone(r.typed)
+
+		if listeners := fn(r.typed[eventType]); len(listeners) > 0 {
+			r.typed[eventType] = listeners
+		} else {
+			delete(r.typed, eventType)
+		}
+	})
+}
+
+// indexPatterns builds a trie of the patterns among the event names, or returns nil if there are none.
+func indexPatterns(listeners map[string]*listenerSet) *patternNode {
+	var root *patternNode
+	for eventName := range listeners {
+		if IsPattern(eventName) {
+			if root == nil {
+				root = newPatternNode()
+			}
+			root.insert(eventName)
+		}
+	}
+
+	return root
+}
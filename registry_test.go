@@ -0,0 +1,197 @@
+package event_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_EqualPrioritiesKeepRegistrationOrder(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var callOrder []int
+	for i := 0; i < 20; i++ {
+		priority := 0
+		if i%3 == 0 {
+			priority = 10
+		}
+		dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+			callOrder = append(callOrder, i)
+			return true
+		}), priority)
+	}
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.Equal(t, []int{0, 3, 6, 9, 12, 15, 18, 1, 2, 4, 5, 7, 8, 10, 11, 13, 14, 16, 17, 19}, callOrder)
+}
+
+func TestRegistry_EqualPrioritiesAcrossPatterns(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var callOrder []string
+	record := func(name string) event.Listener {
+		return event.ListenerFunc(func(e event.Event) bool {
+			callOrder = append(callOrder, name)
+			return true
+		})
+	}
+
+	dispatcher.AddListener("user.*", record("first"))
+	dispatcher.AddListener("user.created", record("second"))
+	dispatcher.AddListener("**", record("third"))
+	dispatcher.AddListener("user.created", record("fourth"))
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.Equal(t, []string{"first", "second", "third", "fourth"}, callOrder)
+}
+
+func TestRegistry_ChangesDuringDispatchApplyToNextDispatch(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	added := event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	})
+
+	var sub *event.Subscription
+	sub, _ = dispatcher.Listen("user.created", event.ListenerFunc(func(e event.Event) bool {
+		sub.Unsubscribe()
+		dispatcher.AddListener("user.created", added)
+		return true
+	}), event.WithPriority(10))
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	assert.Equal(t, 0, calls)
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRegistry_DispatchDoesNotAllocate(t *testing.T) {
+	// Only listeners registered by exact event name, without pattern or type listeners, are covered
+	dispatcher := event.NewDispatcher()
+	for i := 0; i < 5; i++ {
+		dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+			return true
+		}), i%2)
+	}
+	_, err := dispatcher.Listen("user.created", event.ListenerFunc(func(e event.Event) bool {
+		return true
+	}), event.WithName("audit"), event.Where(event.ArgEquals("source", "signup")))
+	require.NoError(t, err)
+	e := event.NewEvent("user.created")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dispatcher.Dispatch(e)
+	})
+
+	assert.Zero(t, allocs)
+}
+
+func TestRegistry_ConcurrentChangesAndDispatches(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("user.*", event.ListenerFunc(func(e event.Event) bool { return true }))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sub, _ := dispatcher.Listen(fmt.Sprintf("user.%d", j%5), event.ListenerFunc(func(e event.Event) bool {
+					return true
+				}))
+				unsub, _ := dispatcher.Listen("order.**", event.ListenerFunc(func(e event.Event) bool {
+					return true
+				}))
+				sub.Unsubscribe()
+				unsub.Unsubscribe()
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				dispatcher.Dispatch(event.NewEvent(fmt.Sprintf("user.%d", j%5)))
+				_, _ = dispatcher.DispatchContext(context.Background(), event.NewEvent("order.created"))
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func benchmarkDispatcher(listeners int) *event.EventDispatcher {
+	dispatcher := event.NewDispatcher()
+	for i := 0; i < listeners; i++ {
+		dispatcher.AddListener("user.created", event.ListenerFunc(func(e event.Event) bool {
+			return true
+		}), i%3)
+	}
+
+	return dispatcher
+}
+
+func BenchmarkDispatch(b *testing.B) {
+	for _, listeners := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("listeners=%d", listeners), func(b *testing.B) {
+			dispatcher := benchmarkDispatcher(listeners)
+			e := event.NewEvent("user.created")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dispatcher.Dispatch(e)
+			}
+		})
+	}
+}
+
+func BenchmarkDispatch_Parallel(b *testing.B) {
+	dispatcher := benchmarkDispatcher(10)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		e := event.NewEvent("user.created")
+		for pb.Next() {
+			dispatcher.Dispatch(e)
+		}
+	})
+}
+
+func BenchmarkDispatch_WithPattern(b *testing.B) {
+	dispatcher := benchmarkDispatcher(10)
+	dispatcher.AddListener("user.*", event.ListenerFunc(func(e event.Event) bool {
+		return true
+	}))
+	e := event.NewEvent("user.created")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dispatcher.Dispatch(e)
+	}
+}
+
+func BenchmarkListen(b *testing.B) {
+	dispatcher := benchmarkDispatcher(10)
+	listener := event.ListenerFunc(func(e event.Event) bool {
+		return true
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sub, _ := dispatcher.Listen("user.created", listener, event.WithPriority(i%3))
+		sub.Unsubscribe()
+	}
+}
@@ -0,0 +1,171 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrListenerFailed is recorded for listeners that return false from Handle or HandleContext.
+var ErrListenerFailed = errors.New("event: listener reported failure")
+
+// Outcome describes what happened to a listener during a dispatch.
+type Outcome int
+
+const (
+	// OutcomeHandled means the listener ran and succeeded.
+	OutcomeHandled Outcome = iota
+
+	// OutcomeFailed means the listener ran and returned false or an error.
+	OutcomeFailed
+
+	// OutcomeSkipped means the listener did not run because propagation was stopped,
+	// an earlier listener panicked under PanicRecoverStop or timed out under TimeoutStop,
+	// or the listener had reached the limit set with Once, Times or Until.
+	OutcomeSkipped
+
+	// OutcomeCanceled means the listener did not run because the context was done.
+	OutcomeCanceled
+
+	// OutcomePanicked means the listener ran and panicked, and the panic was recovered.
+	OutcomePanicked
+
+	// OutcomeFiltered means the listener did not run because the event did not match its filters.
+	OutcomeFiltered
+
+	// OutcomeTimedOut means the listener ran but exceeded its time budget, and the dispatch
+	// stopped waiting for it.
+	OutcomeTimedOut
+)
+
+// String returns the name of the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeHandled:
+		return "handled"
+	case OutcomeFailed:
+		return "failed"
+	case OutcomeSkipped:
+		return "skipped"
+	case OutcomeCanceled:
+		return "canceled"
+	case OutcomePanicked:
+		return "panicked"
+	case OutcomeFiltered:
+		return "filtered"
+	case OutcomeTimedOut:
+		return "timed out"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// ListenerError is the error recorded when a listener fails.
+type ListenerError struct {
+	// EventName is the name of the event being dispatched.
+	EventName string
+
+	// Listener is the listener that failed.
+	Listener Listener
+
+	// Err is the error returned by the listener, or ErrListenerFailed.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ListenerError) Error() string {
+	return fmt.Sprintf("event: listener %T failed handling %q: %v", e.Listener, e.EventName, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ListenerError) Unwrap() error {
+	return e.Err
+}
+
+// ListenerResult records the outcome of a single listener during a dispatch.
+type ListenerResult struct {
+	// Listener is the registered listener.
+	Listener Listener
+
+	// Priority is the priority the listener was registered with.
+	Priority int
+
+	// Outcome describes whether the listener ran and succeeded.
+	Outcome Outcome
+
+	// Err is the listener's error when the outcome is OutcomeFailed, OutcomePanicked or OutcomeTimedOut.
+	Err error
+
+	// Filter is the filter the event did not match when the outcome is OutcomeFiltered.
+	Filter Filter
+
+	// Duration is how long the listener took. It is zero for listeners that did not run.
+	// For retried listeners it is the duration of the last call.
+	Duration time.Duration
+
+	// Attempts is the number of times the listener was called, more than one if it was retried.
+	Attempts int
+}
+
+// Ran reports whether the listener was called.
+func (r ListenerResult) Ran() bool {
+	switch r.Outcome {
+	case OutcomeHandled, OutcomeFailed, OutcomePanicked, OutcomeTimedOut:
+		return true
+	default:
+		return false
+	}
+}
+
+// DispatchResult records what happened to each listener while dispatching an event.
+type DispatchResult struct {
+	// Event is the dispatched event.
+	Event Event
+
+	// Listeners holds one result per listener, in the order they were considered.
+	Listeners []ListenerResult
+
+	// PropagationStopped reports whether a listener stopped propagation.
+	PropagationStopped bool
+
+	// ContextErr is the cause of the cancellation if the context was done before all listeners ran.
+	ContextErr error
+
+	// MiddlewareErr is the error returned by the dispatch middleware, if it differs from the listener errors.
+	MiddlewareErr error
+}
+
+// Err returns the listener, context and middleware errors joined with errors.Join,
+// or nil if every listener that ran succeeded.
+//
+// Each listener error is a *ListenerError and can be inspected with errors.As.
+func (r *DispatchResult) Err() error {
+	var errs []error
+	for _, l := range r.Listeners {
+		if l.Err != nil {
+			errs = append(errs, l.Err)
+		}
+	}
+
+	if r.ContextErr != nil {
+		errs = append(errs, r.ContextErr)
+	}
+
+	if r.MiddlewareErr != nil {
+		errs = append(errs, r.MiddlewareErr)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Failed returns the results of the listeners that failed, panicked or timed out.
+func (r *DispatchResult) Failed() []ListenerResult {
+	var failed []ListenerResult
+	for _, l := range r.Listeners {
+		if l.Outcome == OutcomeFailed || l.Outcome == OutcomePanicked || l.Outcome == OutcomeTimedOut {
+			failed = append(failed, l)
+		}
+	}
+
+	return failed
+}
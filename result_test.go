@@ -0,0 +1,93 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatchResult_RecordsOutcomes(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	errPayment := errors.New("payment declined")
+
+	dispatcher.AddListener("order.created", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		return nil
+	}), 100)
+
+	dispatcher.AddListener("order.created", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		return errPayment
+	}), 90)
+
+	dispatcher.AddListener("order.created", event.ListenerFunc(func(e event.Event) bool {
+		e.StopPropagation()
+		return false
+	}), 80)
+
+	dispatcher.AddListener("order.created", &TestListener{}, 70)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	require.Len(t, result.Listeners, 4)
+	assert.Equal(t, event.OutcomeHandled, result.Listeners[0].Outcome)
+	assert.Equal(t, event.OutcomeFailed, result.Listeners[1].Outcome)
+	assert.Equal(t, event.OutcomeFailed, result.Listeners[2].Outcome)
+	assert.Equal(t, event.OutcomeSkipped, result.Listeners[3].Outcome)
+
+	assert.True(t, result.Listeners[1].Ran())
+	assert.False(t, result.Listeners[3].Ran())
+	assert.Zero(t, result.Listeners[3].Duration)
+	assert.True(t, result.PropagationStopped)
+	assert.Len(t, result.Failed(), 2)
+
+	err := result.Err()
+	assert.ErrorIs(t, err, errPayment)
+	assert.ErrorIs(t, err, event.ErrListenerFailed)
+
+	var listenerErr *event.ListenerError
+	require.True(t, errors.As(err, &listenerErr))
+	assert.Equal(t, "order.created", listenerErr.EventName)
+}
+
+func TestDispatchResult_Canceled(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("order.created", &TestListener{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := dispatcher.DispatchWithResult(ctx, event.NewEvent("order.created"))
+
+	require.Len(t, result.Listeners, 1)
+	assert.Equal(t, event.OutcomeCanceled, result.Listeners[0].Outcome)
+	assert.ErrorIs(t, result.Err(), context.Canceled)
+}
+
+func TestDispatchResult_NoErrors(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("order.created", &TestListener{})
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	assert.NoError(t, result.Err())
+	assert.Empty(t, result.Failed())
+}
+
+func TestOutcome_String(t *testing.T) {
+	assert.Equal(t, "handled", event.OutcomeHandled.String())
+	assert.Equal(t, "failed", event.OutcomeFailed.String())
+	assert.Equal(t, "skipped", event.OutcomeSkipped.String())
+	assert.Equal(t, "canceled", event.OutcomeCanceled.String())
+	assert.Equal(t, "Outcome(42)", event.Outcome(42).String())
+}
+
+func TestErrorListenerFunc_Handle(t *testing.T) {
+	fn := event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		return errors.New("boom")
+	})
+
+	assert.False(t, fn.Handle(event.NewEvent("test.event")))
+}
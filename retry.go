@@ -0,0 +1,264 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy calls a failing listener again, waiting longer before each retry.
+//
+// A listener fails when it returns an error, or false for listeners that return a bool. Every
+// failure is retried unless Retryable rejects it or it is marked with Permanent. Panics are never
+// retried; they are handled according to the recovery policy.
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls, including the first. Values lower than two disable retries.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry.
+	Backoff time.Duration
+
+	// Multiplier is the factor applied to the delay before each further retry.
+	// Values lower than one default to 2.
+	Multiplier float64
+
+	// MaxBackoff caps the delay before a retry. Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Jitter randomizes each delay by up to this fraction of it in either direction, so that
+	// listeners failing together do not retry together. It ranges from 0 to 1.
+	Jitter float64
+
+	// Retryable reports whether an error is worth retrying. If nil, every error is.
+	Retryable func(error) bool
+}
+
+// WithRetry retries the listener according to the policy when it fails. The number of the current
+// call is available through the Attempt method of the event's metadata and, to listeners receiving
+// a context, through AttemptFromContext.
+//
+// During Dispatch and DispatchWithResult the dispatch waits for the retries. An AsyncDispatcher does
+// not hold a worker while waiting: the retries continue in the background and the Future completes
+// once they are done. Background retries are canceled with the context given to DispatchAsyncContext
+// and each one runs within a dispatch timeout of its own.
+func WithRetry(policy RetryPolicy) ListenerOption {
+	return func(o *listenerOptions) {
+		o.retry = &policy
+	}
+}
+
+// Permanent marks an error as permanent, so that it is never retried.
+// The returned error wraps err.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// permanentError is the error returned by Permanent.
+type permanentError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the permanent error.
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// retryable reports whether the listener may be called again after failing with the error.
+func (p *RetryPolicy) retryable(err error) bool {
+	var permanent *permanentError
+	var panicErr *ListenerPanicError
+	switch {
+	case errors.As(err, &permanent), errors.As(err, &panicErr):
+		return false
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// backoff returns the delay before the given retry, counted from one.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(p.Backoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 {
+		delay = min(delay, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		delay += delay * min(p.Jitter, 1) * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// attemptKey is the context key of the number of the current call of a retried listener.
+type attemptKey struct{}
+
+// AttemptFromContext returns the number of the current call, counted from one, of a listener
+// registered with WithRetry. It returns zero for listeners without a retry policy.
+func AttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
+// callAttempt calls the listener with the attempt number in its context and in the metadata
+// of the event.
+func callAttempt(ctx context.Context, event Event, attempt int, call func(context.Context) error) error {
+	if me, ok := event.(MetadataEvent); ok {
+		md := me.Metadata()
+		atomic.StoreInt32(&md.attempt, int32(attempt))
+		defer atomic.StoreInt32(&md.attempt, 0)
+	}
+
+	return call(context.WithValue(ctx, attemptKey{}, attempt))
+}
+
+// callWithRetry calls the listener and retries it according to the policy, waiting for the
+// backoff delays. It returns the number of calls and the error of the last one.
+func callWithRetry(ctx context.Context, event Event, policy *RetryPolicy, call func(context.Context) error) (int, error) {
+	attempt := 1
+	err := callAttempt(ctx, event, attempt, call)
+
+	for err != nil && attempt < policy.MaxAttempts && policy.retryable(err) {
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+
+		attempt++
+		err = callAttempt(ctx, event, attempt, call)
+	}
+
+	return attempt, err
+}
+
+// backgroundRetriesKey is the context key of the retries an AsyncDispatcher runs in the background.
+type backgroundRetriesKey struct{}
+
+// backgroundRetries collects the retries of one asynchronous dispatch, which are run once
+// the worker has finished dispatching the event.
+type backgroundRetries struct {
+	event   Event
+	mu      sync.Mutex
+	pending []*pendingRetry
+}
+
+// pendingRetry is a listener waiting to be called again.
+type pendingRetry struct {
+	at       time.Time
+	attempt  int
+	err      error         // the error of the last call
+	duration time.Duration // the duration of the last call
+	policy   *RetryPolicy
+	ctx      context.Context // the context of the listener's dispatch, without its cancellation
+	call     func(context.Context) error
+	record   func(err error, duration time.Duration, attempts int, final bool)
+}
+
+// add schedules another call of a listener that failed on the given attempt.
+func (b *backgroundRetries) add(retry *pendingRetry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	retry.at = time.Now().Add(retry.policy.backoff(retry.attempt))
+	b.pending = append(b.pending, retry)
+}
+
+// empty reports whether no retries are pending.
+func (b *backgroundRetries) empty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.pending) == 0
+}
+
+// run calls the pending listeners one at a time, in the order they are due, until none is left
+// or the context is done. Retries left once the context is done are abandoned.
+func (b *backgroundRetries) run(ctx context.Context) {
+	for {
+		b.mu.Lock()
+		if len(b.pending) == 0 {
+			b.mu.Unlock()
+			return
+		}
+
+		next := 0
+		for i, retry := range b.pending {
+			if retry.at.Before(b.pending[next].at) {
+				next = i
+			}
+		}
+		retry := b.pending[next]
+		b.pending = append(b.pending[:next], b.pending[next+1:]...)
+		b.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(retry.at))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			b.abandon(retry)
+			return
+		case <-timer.C:
+		}
+
+		retry.attempt++
+		start := time.Now()
+		attemptCtx, cancel := retryContext(ctx, retry.ctx)
+		retry.err = callAttempt(attemptCtx, b.event, retry.attempt, retry.call)
+		cancel()
+		retry.duration = time.Since(start)
+
+		again := retry.err != nil && retry.attempt < retry.policy.MaxAttempts && retry.policy.retryable(retry.err)
+		retry.record(retry.err, retry.duration, retry.attempt, !again)
+		if again {
+			b.add(retry)
+		}
+	}
+}
+
+// retryContext returns a context carrying the values of the listener's dispatch context, which
+// has ended by the time the retry runs, that is canceled together with the context of the retries.
+func retryContext(ctx, dispatchCtx context.Context) (context.Context, context.CancelFunc) {
+	retryCtx, cancel := context.WithCancelCause(dispatchCtx)
+	stop := context.AfterFunc(ctx, func() {
+		cancel(context.Cause(ctx))
+	})
+
+	return retryCtx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// abandon records the last failure of the given retry and of every pending one as final,
+// as a synchronous dispatch does when its context is done during the backoff.
+func (b *backgroundRetries) abandon(retry *pendingRetry) {
+	b.mu.Lock()
+	abandoned := append([]*pendingRetry{retry}, b.pending...)
+	b.pending = nil
+	b.mu.Unlock()
+
+	for _, retry := range abandoned {
+		retry.record(retry.err, retry.duration, retry.attempt, true)
+	}
+}
@@ -0,0 +1,319 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("service unavailable")
+
+// flaky returns a listener that fails until its given call, recording the attempt seen in its context.
+func flaky(succeedOn int, attempts *[]int) event.Listener {
+	calls := 0
+	return event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		calls++
+		*attempts = append(*attempts, event.AttemptFromContext(ctx))
+		if calls < succeedOn {
+			return errUnavailable
+		}
+		return nil
+	})
+}
+
+func TestRetry_Succeeds(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var attempts []int
+	_, err := dispatcher.Listen("order.created", flaky(3, &attempts),
+		event.WithRetry(event.RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond}))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	require.NoError(t, result.Err())
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+	assert.Equal(t, event.OutcomeHandled, result.Listeners[0].Outcome)
+	assert.Equal(t, 3, result.Listeners[0].Attempts)
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var attempts []int
+	_, err := dispatcher.Listen("order.created", flaky(10, &attempts),
+		event.WithRetry(event.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	assert.ErrorIs(t, result.Err(), errUnavailable)
+	assert.Equal(t, event.OutcomeFailed, result.Listeners[0].Outcome)
+	assert.Equal(t, 3, result.Listeners[0].Attempts)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func TestRetry_ConcurrentDispatchesCountTheirOwnAttempts(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var mu sync.Mutex
+	var attempts []int
+	_, err := dispatcher.Listen("order.created", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		attempt := event.AttemptFromContext(ctx)
+
+		// Shared by the dispatches, so only safe to read, not exact
+		_ = e.(event.MetadataEvent).Metadata().Attempt()
+
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, attempt)
+		if attempt < 2 {
+			return errUnavailable
+		}
+		return nil
+	}), event.WithRetry(event.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	require.NoError(t, err)
+
+	e := event.NewEvent("order.created")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dispatcher.Dispatch(e)
+		}()
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{1, 1, 1, 1, 2, 2, 2, 2}, attempts)
+}
+
+func TestRetry_BoolListener(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var attempts []int
+	_, err := dispatcher.Listen("order.created", event.ListenerFunc(func(e event.Event) bool {
+		// Plain listeners read the attempt from the metadata
+		attempts = append(attempts, e.(event.MetadataEvent).Metadata().Attempt())
+		return len(attempts) == 2
+	}), event.WithRetry(event.RetryPolicy{MaxAttempts: 3}))
+	require.NoError(t, err)
+
+	e := event.NewEvent("order.created")
+	dispatcher.Dispatch(e)
+
+	assert.Equal(t, []int{1, 2}, attempts)
+	assert.Zero(t, e.Metadata().Attempt())
+}
+
+func TestRetry_Classification(t *testing.T) {
+	errInvalid := errors.New("invalid order")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"retryable", errUnavailable, 3},
+		{"rejected by classifier", errInvalid, 1},
+		{"permanent", event.Permanent(errUnavailable), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := event.NewDispatcher()
+
+			calls := 0
+			_, err := dispatcher.Listen("order.created", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+				calls++
+				return tt.err
+			}), event.WithRetry(event.RetryPolicy{
+				MaxAttempts: 3,
+				Retryable: func(err error) bool {
+					return !errors.Is(err, errInvalid)
+				},
+			}))
+			require.NoError(t, err)
+
+			result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+			assert.Equal(t, tt.expected, calls)
+			assert.ErrorIs(t, result.Err(), tt.err)
+		})
+	}
+}
+
+func TestRetry_PanicsAreNotRetried(t *testing.T) {
+	dispatcher := event.NewDispatcher(event.WithRecoveryPolicy(event.PanicRecoverContinue))
+
+	calls := 0
+	_, err := dispatcher.Listen("order.created", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		panic("boom")
+	}), event.WithRetry(event.RetryPolicy{MaxAttempts: 3}))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, event.OutcomePanicked, result.Listeners[0].Outcome)
+}
+
+func TestRetry_Backoff(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var attempts []int
+	_, err := dispatcher.Listen("order.created", flaky(10, &attempts), event.WithRetry(event.RetryPolicy{
+		MaxAttempts: 4,
+		Backoff:     10 * time.Millisecond,
+		Multiplier:  3,
+		MaxBackoff:  15 * time.Millisecond,
+	}))
+	require.NoError(t, err)
+
+	start := time.Now()
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+	elapsed := time.Since(start)
+
+	// 10ms, then 30ms and 90ms capped at 15ms
+	assert.GreaterOrEqual(t, elapsed, 40*time.Millisecond)
+	assert.Less(t, elapsed, 130*time.Millisecond)
+	assert.Len(t, attempts, 4)
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var attempts []int
+	_, err := dispatcher.Listen("order.created", flaky(10, &attempts),
+		event.WithRetry(event.RetryPolicy{MaxAttempts: 5, Backoff: time.Hour}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	result := dispatcher.DispatchWithResult(ctx, event.NewEvent("order.created"))
+
+	assert.Equal(t, []int{1}, attempts)
+	assert.ErrorIs(t, result.Err(), errUnavailable)
+}
+
+type retryingSubscriber struct {
+	calls int
+}
+
+func (s *retryingSubscriber) OnOrderCreated(e event.Event) error {
+	s.calls++
+	if s.calls < 2 {
+		return errUnavailable
+	}
+	return nil
+}
+
+func (s *retryingSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return map[string][]event.SubscriberConfig{
+		"order.created": {
+			{Method: "OnOrderCreated", Retry: &event.RetryPolicy{MaxAttempts: 3}},
+		},
+	}
+}
+
+func TestRetry_SubscriberConfig(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &retryingSubscriber{}
+
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	require.NoError(t, result.Err())
+	assert.Equal(t, 2, subscriber.calls)
+}
+
+func TestRetry_AsyncDoesNotBlockWorkers(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(event.NewDispatcher(), event.WithWorkers(1))
+	defer dispatcher.Shutdown(context.Background())
+
+	var attempts []int
+	_, err := dispatcher.Listen("payment.charge", flaky(2, &attempts),
+		event.WithRetry(event.RetryPolicy{MaxAttempts: 3, Backoff: 50 * time.Millisecond}))
+	require.NoError(t, err)
+
+	charge, err := dispatcher.DispatchAsync(event.NewEvent("payment.charge"))
+	require.NoError(t, err)
+	email, err := dispatcher.DispatchAsync(event.NewEvent("email.send"))
+	require.NoError(t, err)
+
+	// The single worker handles the next event while the first one waits for its retry
+	_, err = email.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, charge.Result())
+
+	_, err = charge.Wait(context.Background())
+	require.NoError(t, err)
+
+	result := charge.Result()
+	require.NoError(t, result.Err())
+	assert.Equal(t, 2, result.Listeners[0].Attempts)
+	assert.Equal(t, []int{1, 2}, attempts)
+}
+
+func TestRetry_AsyncShutdownWaitsForRetries(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(event.NewDispatcher(), event.WithWorkers(1))
+
+	var attempts []int
+	_, err := dispatcher.Listen("payment.charge", flaky(3, &attempts),
+		event.WithRetry(event.RetryPolicy{MaxAttempts: 3, Backoff: 5 * time.Millisecond}))
+	require.NoError(t, err)
+
+	future, err := dispatcher.DispatchAsync(event.NewEvent("payment.charge"))
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.Shutdown(context.Background()))
+
+	select {
+	case <-future.Done():
+	default:
+		t.Fatal("shutdown returned before the retries were done")
+	}
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func TestRetry_AsyncWithDispatchTimeout(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(event.NewDispatcher(event.WithDispatchTimeout(time.Second)))
+	defer dispatcher.Shutdown(context.Background())
+
+	var ctxErrs []error
+	calls := 0
+	_, err := dispatcher.Listen("payment.charge", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		calls++
+		ctxErrs = append(ctxErrs, ctx.Err())
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if calls < 3 {
+			return errUnavailable
+		}
+		return nil
+	}), event.WithRetry(event.RetryPolicy{MaxAttempts: 3, Backoff: 5 * time.Millisecond}))
+	require.NoError(t, err)
+
+	future, err := dispatcher.DispatchAsync(event.NewEvent("payment.charge"))
+	require.NoError(t, err)
+
+	_, err = future.Wait(context.Background())
+	require.NoError(t, err)
+
+	// Every retry runs within a dispatch timeout of its own, not the expired one of the first call
+	result := future.Result()
+	require.NoError(t, result.Err())
+	assert.Equal(t, 3, result.Listeners[0].Attempts)
+	assert.Equal(t, []error{nil, nil, nil}, ctxErrs)
+}
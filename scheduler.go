@@ -0,0 +1,427 @@
+package event
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrSchedulerStopped is returned when an event is scheduled on a stopped Scheduler.
+var ErrSchedulerStopped = errors.New("event: scheduler is stopped")
+
+// Clock tells the time and calls functions after a delay. Schedulers use the system clock
+// unless another one is set with WithClock, for example a fake clock in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// AfterFunc calls f in its own goroutine once the duration has elapsed.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending call started by Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the call from happening. It reports whether the call was still pending.
+	Stop() bool
+}
+
+// systemClock is the Clock backed by the time package.
+type systemClock struct{}
+
+// Now implements the Clock interface.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc implements the Clock interface.
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// ScheduledEntry describes a scheduled dispatch.
+type ScheduledEntry struct {
+	// ID identifies the scheduled dispatch.
+	ID string
+
+	// Event is the event to dispatch. Recurring dispatches send a new event with its name and
+	// arguments every time.
+	Event Event
+
+	// At is the time the event is due next.
+	At time.Time
+
+	// Spec is the schedule specification of a recurring dispatch, or empty for a single dispatch.
+	Spec string
+}
+
+// ScheduleStore persists scheduled dispatches so that they survive a restart.
+//
+// The scheduler saves an entry when it is scheduled and every time a recurring dispatch moves
+// to its next time, and deletes it once a single dispatch has been sent or a dispatch is cancelled.
+// Entries left in the store are scheduled again by Restore.
+type ScheduleStore interface {
+	// Save stores the entry, replacing any entry with the same ID.
+	Save(entry ScheduledEntry) error
+
+	// Delete removes the entry with the given ID.
+	Delete(id string) error
+
+	// Load returns the stored entries.
+	Load() ([]ScheduledEntry, error)
+}
+
+// SchedulerOption configures a Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithClock sets the clock of the scheduler.
+func WithClock(clock Clock) SchedulerOption {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithScheduleStore sets the store that persists the pending dispatches of the scheduler.
+func WithScheduleStore(store ScheduleStore) SchedulerOption {
+	return func(s *Scheduler) {
+		s.store = store
+	}
+}
+
+// WithScheduleErrorHandler sets the function called with the store errors that occur while
+// dispatching or cancelling, which cannot be returned to a caller.
+func WithScheduleErrorHandler(handler func(error)) SchedulerOption {
+	return func(s *Scheduler) {
+		s.errorHandler = handler
+	}
+}
+
+// Scheduler dispatches events at a later time, once or on a recurring schedule.
+//
+// Pending dispatches are kept in a heap ordered by their time, and a single timer waits for the
+// earliest one, so scheduling many events does not start a goroutine per event. The events are
+// dispatched from the timer's goroutine.
+type Scheduler struct {
+	dispatcher   Dispatcher
+	clock        Clock
+	store        ScheduleStore
+	errorHandler func(error)
+
+	mu      sync.Mutex
+	queue   scheduleQueue
+	items   map[string]*scheduledItem
+	timer   Timer
+	stopped bool
+}
+
+// NewScheduler creates a scheduler that dispatches events to the dispatcher.
+func NewScheduler(d Dispatcher, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		dispatcher: d,
+		clock:      systemClock{},
+		items:      make(map[string]*scheduledItem),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Scheduled is a handle to a scheduled dispatch.
+type Scheduled struct {
+	id        string
+	scheduler *Scheduler
+}
+
+// ID returns the ID of the scheduled dispatch, as found in its ScheduledEntry.
+func (s *Scheduled) ID() string {
+	return s.id
+}
+
+// Cancel cancels the dispatch and deletes it from the store.
+// It reports whether the dispatch was still pending.
+func (s *Scheduled) Cancel() bool {
+	return s.scheduler.cancel(s.id)
+}
+
+// DispatchAt dispatches the event at the given time. Times in the past dispatch it as soon as possible.
+func (s *Scheduler) DispatchAt(e Event, at time.Time) (*Scheduled, error) {
+	return s.schedule(ScheduledEntry{ID: newEventID(), Event: e, At: at}, nil)
+}
+
+// DispatchAfter dispatches the event once the delay has elapsed.
+func (s *Scheduler) DispatchAfter(e Event, delay time.Duration) (*Scheduled, error) {
+	return s.DispatchAt(e, s.clock.Now().Add(delay))
+}
+
+// Every dispatches a new event with the given name and arguments at every interval.
+func (s *Scheduler) Every(interval time.Duration, eventName string, arguments ...map[string]interface{}) (*Scheduled, error) {
+	return s.Cron("@every "+interval.String(), eventName, arguments...)
+}
+
+// Cron dispatches a new event with the given name and arguments at the times of the schedule
+// specification, which is parsed by ParseSchedule.
+//
+//	scheduler.Cron("@hourly", "billing.tick")
+//	scheduler.Cron("30 2 * * 1-5", "report.daily", map[string]interface{}{"format": "pdf"})
+func (s *Scheduler) Cron(spec string, eventName string, arguments ...map[string]interface{}) (*Scheduled, error) {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	next := schedule.Next(s.clock.Now())
+	if next.IsZero() {
+		return nil, fmt.Errorf("%w %q: never due", ErrInvalidSchedule, spec)
+	}
+
+	return s.schedule(ScheduledEntry{
+		ID:    newEventID(),
+		Event: NewEvent(eventName, arguments...),
+		At:    next,
+		Spec:  spec,
+	}, schedule)
+}
+
+// Restore schedules the entries of the store again, for example after a restart.
+// Entries that fell due while the scheduler was not running are dispatched right away,
+// once, and recurring entries then continue with their schedule.
+func (s *Scheduler) Restore() error {
+	if s.store == nil {
+		return nil
+	}
+
+	entries, err := s.store.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return ErrSchedulerStopped
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if _, ok := s.items[entry.ID]; ok {
+			continue
+		}
+
+		item := &scheduledItem{entry: entry}
+		if entry.Spec != "" {
+			if item.schedule, err = ParseSchedule(entry.Spec); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		s.push(item)
+	}
+	s.arm()
+
+	return errors.Join(errs...)
+}
+
+// Stop stops dispatching events. Pending dispatches stay in the store to be restored later.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// Pending returns the pending dispatches, ordered by the time they are due.
+func (s *Scheduler) Pending() []ScheduledEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The items belong to the queue, so they are copied and sorted rather than popped
+	entries := make([]ScheduledEntry, 0, len(s.queue))
+	for _, item := range s.queue {
+		entries = append(entries, item.entry)
+	}
+
+	slices.SortFunc(entries, func(a, b ScheduledEntry) int {
+		return a.At.Compare(b.At)
+	})
+
+	return entries
+}
+
+// schedule stores and queues the entry.
+func (s *Scheduler) schedule(entry ScheduledEntry, schedule Schedule) (*Scheduled, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return nil, ErrSchedulerStopped
+	}
+
+	if s.store != nil {
+		if err := s.store.Save(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	s.push(&scheduledItem{entry: entry, schedule: schedule})
+	s.arm()
+
+	return &Scheduled{id: entry.ID, scheduler: s}, nil
+}
+
+// cancel removes the pending dispatch with the given ID.
+func (s *Scheduler) cancel(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return false
+	}
+
+	heap.Remove(&s.queue, item.index)
+	delete(s.items, id)
+	s.arm()
+
+	if s.store != nil {
+		s.report(s.store.Delete(id))
+	}
+
+	return true
+}
+
+// push queues the item. The caller must hold the lock.
+func (s *Scheduler) push(item *scheduledItem) {
+	heap.Push(&s.queue, item)
+	s.items[item.entry.ID] = item
+}
+
+// arm sets the timer for the earliest pending dispatch. The caller must hold the lock.
+func (s *Scheduler) arm() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	if s.stopped || len(s.queue) == 0 {
+		return
+	}
+
+	s.timer = s.clock.AfterFunc(max(s.queue[0].entry.At.Sub(s.clock.Now()), 0), s.fire)
+}
+
+// fire dispatches the events that are due and moves recurring dispatches to their next time.
+func (s *Scheduler) fire() {
+	s.mu.Lock()
+
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+
+	now := s.clock.Now()
+
+	var due []ScheduledEntry
+	for len(s.queue) > 0 && !s.queue[0].entry.At.After(now) {
+		item := heap.Pop(&s.queue).(*scheduledItem)
+		due = append(due, item.entry)
+
+		if item.schedule == nil {
+			delete(s.items, item.entry.ID)
+			continue
+		}
+
+		// Missed times are skipped rather than caught up
+		item.entry.At = item.schedule.Next(now)
+		if item.entry.At.IsZero() {
+			delete(s.items, item.entry.ID)
+			if s.store != nil {
+				s.report(s.store.Delete(item.entry.ID))
+			}
+			continue
+		}
+
+		heap.Push(&s.queue, item)
+		if s.store != nil {
+			s.report(s.store.Save(item.entry))
+		}
+	}
+
+	s.arm()
+	s.mu.Unlock()
+
+	for _, entry := range due {
+		if entry.Spec == "" {
+			s.dispatcher.Dispatch(entry.Event)
+
+			// Deleting after the dispatch delivers the event at least once across a restart
+			if s.store != nil {
+				s.report(s.store.Delete(entry.ID))
+			}
+			continue
+		}
+
+		s.dispatcher.Dispatch(NewEvent(entry.Event.Name(), entry.Event.Arguments()))
+	}
+}
+
+// report passes a store error to the error handler.
+func (s *Scheduler) report(err error) {
+	if err != nil && s.errorHandler != nil {
+		s.errorHandler(err)
+	}
+}
+
+// scheduledItem is a pending dispatch in the queue of a Scheduler.
+type scheduledItem struct {
+	entry ScheduledEntry
+
+	// schedule computes the next time of a recurring dispatch, or is nil for a single dispatch.
+	schedule Schedule
+
+	// index is the position of the item in the queue.
+	index int
+}
+
+// scheduleQueue is a min-heap of pending dispatches ordered by their time.
+type scheduleQueue []*scheduledItem
+
+// Len implements heap.Interface.
+func (q scheduleQueue) Len() int { return len(q) }
+
+// Less implements heap.Interface.
+func (q scheduleQueue) Less(i, j int) bool { return q[i].entry.At.Before(q[j].entry.At) }
+
+// Swap implements heap.Interface.
+func (q scheduleQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+// Push implements heap.Interface.
+func (q *scheduleQueue) Push(x any) {
+	item := x.(*scheduledItem)
+	item.index = len(*q)
+	*q = append(*q, item)
+}
+
+// Pop implements heap.Interface.
+func (q *scheduleQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+
+	return item
+}
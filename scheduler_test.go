@@ -0,0 +1,346 @@
+package event_test
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock whose time only moves when advanced, running due timers synchronously.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	at    time.Time
+	f     func()
+	done  bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) event.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+
+	return t
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	pending := !t.done
+	t.done = true
+
+	return pending
+}
+
+// Advance moves the time forward, running every timer that falls due on the way.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		var next *fakeTimer
+		for _, t := range c.timers {
+			if !t.done && !t.at.After(end) && (next == nil || t.at.Before(next.at)) {
+				next = t
+			}
+		}
+
+		if next == nil {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+
+		next.done = true
+		if next.at.After(c.now) {
+			c.now = next.at
+		}
+		c.mu.Unlock()
+
+		next.f()
+	}
+}
+
+// memoryStore is a ScheduleStore keeping entries in memory.
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]event.ScheduledEntry
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: make(map[string]event.ScheduledEntry)}
+}
+
+func (s *memoryStore) Save(entry event.ScheduledEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.ID] = entry
+	return nil
+}
+
+func (s *memoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, id)
+	return nil
+}
+
+func (s *memoryStore) Load() ([]event.ScheduledEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]event.ScheduledEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+
+	return entries, nil
+}
+
+func (s *memoryStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// recordDispatches returns a dispatcher that records the names of the events it receives.
+func recordDispatches(calls *[]string) *event.EventDispatcher {
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("**", event.ListenerFunc(func(e event.Event) bool {
+		*calls = append(*calls, e.Name())
+		return true
+	}))
+
+	return dispatcher
+}
+
+func TestScheduler_DispatchAfter(t *testing.T) {
+	var calls []string
+	clock := newFakeClock()
+	scheduler := event.NewScheduler(recordDispatches(&calls), event.WithClock(clock))
+
+	_, err := scheduler.DispatchAfter(event.NewEvent("second"), 2*time.Minute)
+	require.NoError(t, err)
+	_, err = scheduler.DispatchAt(event.NewEvent("first"), clock.Now().Add(time.Minute))
+	require.NoError(t, err)
+	_, err = scheduler.DispatchAfter(event.NewEvent("third"), time.Hour)
+	require.NoError(t, err)
+
+	clock.Advance(30 * time.Second)
+	assert.Empty(t, calls)
+
+	clock.Advance(2 * time.Minute)
+	assert.Equal(t, []string{"first", "second"}, calls)
+
+	require.Len(t, scheduler.Pending(), 1)
+	assert.Equal(t, "third", scheduler.Pending()[0].Event.Name())
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	var calls []string
+	clock := newFakeClock()
+	scheduler := event.NewScheduler(recordDispatches(&calls), event.WithClock(clock))
+
+	scheduled, err := scheduler.DispatchAfter(event.NewEvent("reminder"), time.Minute)
+	require.NoError(t, err)
+
+	assert.True(t, scheduled.Cancel())
+	assert.False(t, scheduled.Cancel())
+
+	clock.Advance(time.Hour)
+	assert.Empty(t, calls)
+	assert.Empty(t, scheduler.Pending())
+}
+
+func TestScheduler_CancelAfterPending(t *testing.T) {
+	var calls []string
+	clock := newFakeClock()
+	scheduler := event.NewScheduler(recordDispatches(&calls), event.WithClock(clock))
+
+	handles := make(map[string]*event.Scheduled)
+	for i, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		scheduled, err := scheduler.DispatchAfter(event.NewEvent(name), time.Duration(i+1)*time.Minute)
+		require.NoError(t, err)
+		handles[name] = scheduled
+	}
+
+	require.Len(t, scheduler.Pending(), 6)
+	assert.True(t, handles["a"].Cancel())
+
+	var pending []string
+	for _, entry := range scheduler.Pending() {
+		pending = append(pending, entry.Event.Name())
+	}
+	assert.Equal(t, []string{"b", "c", "d", "e", "f"}, pending)
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, []string{"b", "c", "d", "e", "f"}, calls)
+}
+
+func TestScheduler_Every(t *testing.T) {
+	var calls []string
+	clock := newFakeClock()
+	scheduler := event.NewScheduler(recordDispatches(&calls), event.WithClock(clock))
+
+	scheduled, err := scheduler.Every(time.Hour, "billing.tick")
+	require.NoError(t, err)
+
+	clock.Advance(3*time.Hour + time.Minute)
+	assert.Equal(t, []string{"billing.tick", "billing.tick", "billing.tick"}, calls)
+
+	scheduled.Cancel()
+	clock.Advance(3 * time.Hour)
+	assert.Len(t, calls, 3)
+}
+
+func TestScheduler_Cron(t *testing.T) {
+	clock := newFakeClock()
+
+	var at []time.Time
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("report.daily", event.ListenerFunc(func(e event.Event) bool {
+		assert.Equal(t, "pdf", e.Arguments()["format"])
+		at = append(at, clock.Now())
+		return true
+	}))
+
+	scheduler := event.NewScheduler(dispatcher, event.WithClock(clock))
+	_, err := scheduler.Cron("30 2 * * *", "report.daily", map[string]interface{}{"format": "pdf"})
+	require.NoError(t, err)
+
+	clock.Advance(48 * time.Hour)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, time.January, 1, 2, 30, 0, 0, time.UTC),
+		time.Date(2024, time.January, 2, 2, 30, 0, 0, time.UTC),
+	}, at)
+
+	_, err = scheduler.Cron("never", "report.daily")
+	assert.ErrorIs(t, err, event.ErrInvalidSchedule)
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	var calls []string
+	clock := newFakeClock()
+	scheduler := event.NewScheduler(recordDispatches(&calls), event.WithClock(clock))
+
+	_, err := scheduler.DispatchAfter(event.NewEvent("reminder"), time.Minute)
+	require.NoError(t, err)
+
+	scheduler.Stop()
+	clock.Advance(time.Hour)
+	assert.Empty(t, calls)
+
+	_, err = scheduler.DispatchAfter(event.NewEvent("reminder"), time.Minute)
+	assert.ErrorIs(t, err, event.ErrSchedulerStopped)
+}
+
+func TestScheduler_Restore(t *testing.T) {
+	store := newMemoryStore()
+	clock := newFakeClock()
+
+	var before []string
+	scheduler := event.NewScheduler(recordDispatches(&before), event.WithClock(clock), event.WithScheduleStore(store))
+
+	_, err := scheduler.DispatchAfter(event.NewEvent("sent"), time.Minute)
+	require.NoError(t, err)
+	_, err = scheduler.DispatchAfter(event.NewEvent("pending"), time.Hour)
+	require.NoError(t, err)
+	cancelled, err := scheduler.DispatchAfter(event.NewEvent("cancelled"), time.Hour)
+	require.NoError(t, err)
+	_, err = scheduler.Every(30*time.Minute, "billing.tick")
+	require.NoError(t, err)
+
+	cancelled.Cancel()
+	clock.Advance(2 * time.Minute)
+	assert.Equal(t, []string{"sent"}, before)
+	assert.Equal(t, 2, store.len())
+
+	// Simulate a restart while the pending events fall due
+	scheduler.Stop()
+	clock.Advance(2 * time.Hour)
+
+	var after []string
+	restarted := event.NewScheduler(recordDispatches(&after), event.WithClock(clock), event.WithScheduleStore(store))
+	require.NoError(t, restarted.Restore())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, []string{"billing.tick", "pending"}, after)
+
+	clock.Advance(30 * time.Minute)
+	assert.Equal(t, []string{"billing.tick", "pending", "billing.tick"}, after)
+	assert.Equal(t, 1, store.len())
+}
+
+type failingStore struct {
+	*memoryStore
+	err error
+}
+
+func (s failingStore) Save(entry event.ScheduledEntry) error {
+	return s.err
+}
+
+func TestScheduler_StoreError(t *testing.T) {
+	errStore := errors.New("store unavailable")
+	scheduler := event.NewScheduler(event.NewDispatcher(),
+		event.WithClock(newFakeClock()),
+		event.WithScheduleStore(failingStore{memoryStore: newMemoryStore(), err: errStore}),
+	)
+
+	_, err := scheduler.DispatchAfter(event.NewEvent("reminder"), time.Minute)
+	assert.ErrorIs(t, err, errStore)
+	assert.Empty(t, scheduler.Pending())
+}
+
+func TestScheduler_SystemClock(t *testing.T) {
+	dispatched := make(chan string, 1)
+	dispatcher := event.NewDispatcher()
+	dispatcher.AddListener("reminder", event.ListenerFunc(func(e event.Event) bool {
+		dispatched <- e.Name()
+		return true
+	}))
+
+	scheduler := event.NewScheduler(dispatcher)
+	defer scheduler.Stop()
+
+	_, err := scheduler.DispatchAfter(event.NewEvent("reminder"), 5*time.Millisecond)
+	require.NoError(t, err)
+
+	select {
+	case name := <-dispatched:
+		assert.Equal(t, "reminder", name)
+	case <-time.After(time.Second):
+		t.Fatal("scheduled event was not dispatched")
+	}
+}
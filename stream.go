@@ -0,0 +1,396 @@
+package event
+
+import (
+	"sync"
+	"time"
+)
+
+// Batch is the event emitted by Buffer, Zip and CombineLatest to carry the events it combines.
+type Batch struct {
+	*BaseEvent
+
+	// Events are the combined events, in the order they arrived for Buffer
+	// and in the order of the streams for Zip and CombineLatest.
+	Events []Event
+}
+
+// newBatch creates a batch with the given name holding the events.
+func newBatch(name string, events []Event) *Batch {
+	return &Batch{BaseEvent: NewEvent(name), Events: events}
+}
+
+// Stream is a sequence of dispatched events that operators transform into derived events.
+//
+// A stream starts with From, is transformed with operators such as Map, Filter or Debounce, and ends
+// with To or Sink, whose subscription shuts the whole stream down. Streams are lazy: building one
+// subscribes nothing and starts nothing, and only To or Sink subscribe to the dispatcher and start
+// the operators, each in its own goroutine. Every stream is consumed by exactly one operator or sink.
+//
+//	sub := event.From(dispatcher, "cart.item_added", 64).
+//		Debounce(200 * time.Millisecond).
+//		Map(func(e event.Event) event.Event {
+//			return event.NewEvent("cart.recalculate", e.Arguments())
+//		}).
+//		To(dispatcher)
+//	defer sub.Unsubscribe()
+type Stream struct {
+	// start subscribes the sources of the stream, starts its operators and returns its events.
+	start func() <-chan Event
+	flow  *flow
+}
+
+// flow tracks the sources and goroutines of connected streams so that they shut down together.
+type flow struct {
+	mu    sync.Mutex
+	stops []func()
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// onClose adds a function that stops a source of the flow.
+func (f *flow) onClose(stop func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stops = append(f.stops, stop)
+}
+
+// close stops the sources of the flow and waits until its operators have drained and returned.
+func (f *flow) close() {
+	f.once.Do(func() {
+		f.mu.Lock()
+		stops := f.stops
+		f.mu.Unlock()
+
+		for _, stop := range stops {
+			stop()
+		}
+
+		f.wg.Wait()
+	})
+}
+
+// join returns a flow that also shuts down the flows of the other streams.
+func join(streams []*Stream) *flow {
+	f := streams[0].flow
+	for _, s := range streams[1:] {
+		if s.flow != f {
+			f.onClose(s.flow.close)
+		}
+	}
+
+	return f
+}
+
+// From returns a stream of the events dispatched under the event name or pattern. The events are
+// received as with Subscribe, which takes the same buffer size and options, from the moment the
+// stream is started with To or Sink.
+func From(d *EventDispatcher, pattern string, bufSize int, opts ...ChannelOption) *Stream {
+	f := &flow{}
+
+	return &Stream{flow: f, start: func() <-chan Event {
+		events, cancel := Subscribe(d, pattern, bufSize, opts...)
+		f.onClose(cancel)
+
+		return events
+	}}
+}
+
+// run returns a stream whose operator reads from the stream and writes to the returned stream
+// once it is started. The returned stream is closed once the operator returns.
+func (s *Stream) run(operator func(in <-chan Event, out chan<- Event)) *Stream {
+	return &Stream{flow: s.flow, start: func() <-chan Event {
+		in := s.start()
+		out := make(chan Event)
+
+		s.flow.wg.Add(1)
+		go func() {
+			defer s.flow.wg.Done()
+			defer close(out)
+
+			operator(in, out)
+		}()
+
+		return out
+	}}
+}
+
+// Map replaces every event with the event returned by fn. Returning nil drops the event.
+func (s *Stream) Map(fn func(Event) Event) *Stream {
+	return s.run(func(in <-chan Event, out chan<- Event) {
+		for e := range in {
+			if mapped := fn(e); mapped != nil {
+				out <- mapped
+			}
+		}
+	})
+}
+
+// Filter passes on the events matching every filter and drops the others.
+func (s *Stream) Filter(filters ...Filter) *Stream {
+	return s.run(func(in <-chan Event, out chan<- Event) {
+	events:
+		for e := range in {
+			for _, filter := range filters {
+				if !filter.Match(e) {
+					continue events
+				}
+			}
+
+			out <- e
+		}
+	})
+}
+
+// Debounce passes on an event once no other event has followed it for the wait duration,
+// dropping the events it replaces. A pending event is passed on when the stream shuts down.
+func (s *Stream) Debounce(wait time.Duration) *Stream {
+	return s.run(func(in <-chan Event, out chan<- Event) {
+		timer := time.NewTimer(wait)
+		timer.Stop()
+		defer timer.Stop()
+
+		var pending Event
+		for {
+			select {
+			case e, ok := <-in:
+				if !ok {
+					if pending != nil {
+						out <- pending
+					}
+					return
+				}
+
+				pending = e
+				timer.Reset(wait)
+
+			case <-timer.C:
+				if pending != nil {
+					out <- pending
+					pending = nil
+				}
+			}
+		}
+	})
+}
+
+// Throttle passes on the first event and then drops the events that follow it within the interval.
+func (s *Stream) Throttle(interval time.Duration) *Stream {
+	return s.run(func(in <-chan Event, out chan<- Event) {
+		var last time.Time
+		for e := range in {
+			if now := time.Now(); last.IsZero() || now.Sub(last) >= interval {
+				last = now
+				out <- e
+			}
+		}
+	})
+}
+
+// Buffer collects events into a *Batch with the given name, which it passes on once it holds count
+// events or once the window has passed since its first event. A count or window of zero disables
+// that limit. The events collected so far are passed on when the stream shuts down.
+func (s *Stream) Buffer(name string, count int, window time.Duration) *Stream {
+	return s.run(func(in <-chan Event, out chan<- Event) {
+		timer := time.NewTimer(window)
+		timer.Stop()
+		defer timer.Stop()
+
+		var batch []Event
+		flush := func() {
+			timer.Stop()
+			if len(batch) > 0 {
+				out <- newBatch(name, batch)
+				batch = nil
+			}
+		}
+
+		for {
+			select {
+			case e, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, e)
+				if len(batch) == 1 && window > 0 {
+					timer.Reset(window)
+				}
+				if count > 0 && len(batch) >= count {
+					flush()
+				}
+
+			case <-timer.C:
+				flush()
+			}
+		}
+	})
+}
+
+// Distinct drops events whose key, as returned by fn, equals the key of the event before them.
+// Keys are compared with ==; keys of types that are not comparable never equal each other.
+func (s *Stream) Distinct(key func(Event) interface{}) *Stream {
+	return s.run(func(in <-chan Event, out chan<- Event) {
+		var last interface{}
+		first := true
+		for e := range in {
+			k := key(e)
+			if !first && sameValue(k, last) {
+				continue
+			}
+
+			first = false
+			last = k
+			out <- e
+		}
+	})
+}
+
+// To starts the stream and dispatches every event of the stream to the dispatcher.
+// The returned subscription shuts the stream down as described for Sink.
+func (s *Stream) To(d Dispatcher) *Subscription {
+	return s.Sink(func(e Event) {
+		d.Dispatch(e)
+	})
+}
+
+// Sink starts the stream and calls fn with every event of the stream.
+//
+// Unsubscribing detaches the listeners feeding the stream and returns once the events still under
+// way, including the pending events of Debounce and Buffer, have reached fn. It must therefore not
+// be called from fn.
+func (s *Stream) Sink(fn func(Event)) *Subscription {
+	events := s.start()
+
+	s.flow.wg.Add(1)
+	go func() {
+		defer s.flow.wg.Done()
+
+		for e := range events {
+			fn(e)
+		}
+	}()
+
+	return newSubscription(s.flow.close)
+}
+
+// indexedEvent is an event together with the index of the stream it came from.
+type indexedEvent struct {
+	index int
+	event Event
+}
+
+// indexed starts the streams and merges their events into one channel, which is closed once
+// every stream is.
+func indexed(f *flow, streams []*Stream) <-chan indexedEvent {
+	out := make(chan indexedEvent)
+
+	var wg sync.WaitGroup
+	for i, s := range streams {
+		events := s.start()
+
+		wg.Add(1)
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			defer wg.Done()
+
+			for e := range events {
+				out <- indexedEvent{index: i, event: e}
+			}
+		}()
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// combine returns a stream whose operator reads the events of several streams, joining their flows.
+func combine(streams []*Stream, operator func(in <-chan indexedEvent, out chan<- Event)) *Stream {
+	if len(streams) == 0 {
+		return &Stream{flow: &flow{}, start: func() <-chan Event {
+			out := make(chan Event)
+			close(out)
+			return out
+		}}
+	}
+
+	f := join(streams)
+
+	return &Stream{flow: f, start: func() <-chan Event {
+		in := indexed(f, streams)
+		out := make(chan Event)
+
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			defer close(out)
+
+			operator(in, out)
+		}()
+
+		return out
+	}}
+}
+
+// Merge returns a stream of the events of all the streams, in the order they arrive.
+func Merge(streams ...*Stream) *Stream {
+	return combine(streams, func(in <-chan indexedEvent, out chan<- Event) {
+		for e := range in {
+			out <- e.event
+		}
+	})
+}
+
+// Zip pairs the events of the streams by their position: the first events of every stream form
+// the first *Batch with the given name, the second events the second batch, and so on.
+func Zip(name string, streams ...*Stream) *Stream {
+	return combine(streams, func(in <-chan indexedEvent, out chan<- Event) {
+		queues := make([][]Event, len(streams))
+		for e := range in {
+			queues[e.index] = append(queues[e.index], e.event)
+
+			ready := true
+			for _, queue := range queues {
+				ready = ready && len(queue) > 0
+			}
+			if !ready {
+				continue
+			}
+
+			events := make([]Event, len(queues))
+			for i := range queues {
+				events[i] = queues[i][0]
+				queues[i] = queues[i][1:]
+			}
+			out <- newBatch(name, events)
+		}
+	})
+}
+
+// CombineLatest passes on a *Batch with the given name holding the latest event of every stream
+// whenever one of them has a new event, once every stream has had at least one.
+func CombineLatest(name string, streams ...*Stream) *Stream {
+	return combine(streams, func(in <-chan indexedEvent, out chan<- Event) {
+		latest := make([]Event, len(streams))
+		missing := len(streams)
+		for e := range in {
+			if latest[e.index] == nil {
+				missing--
+			}
+			latest[e.index] = e.event
+
+			if missing == 0 {
+				out <- newBatch(name, append([]Event(nil), latest...))
+			}
+		}
+	})
+}
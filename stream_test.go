@@ -0,0 +1,306 @@
+package event_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collector gathers the events that reach a stream sink.
+type collector struct {
+	mu     sync.Mutex
+	events []event.Event
+}
+
+func (c *collector) add(e event.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.events = append(c.events, e)
+}
+
+func (c *collector) names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, len(c.events))
+	for i, e := range c.events {
+		names[i] = e.Name()
+	}
+
+	return names
+}
+
+func (c *collector) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.events)
+}
+
+func dispatchAll(dispatcher *event.EventDispatcher, names ...string) {
+	for _, name := range names {
+		dispatcher.Dispatch(event.NewEvent(name))
+	}
+}
+
+func TestStream_MapFilter(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.From(dispatcher, "order.*", 8).
+		Filter(event.FilterFunc(func(e event.Event) bool {
+			return e.Name() != "order.paid"
+		})).
+		Map(func(e event.Event) event.Event {
+			if e.Name() == "order.cancelled" {
+				return nil
+			}
+			return event.NewEvent("audit." + e.Name())
+		}).
+		Sink(got.add)
+
+	dispatchAll(dispatcher, "order.created", "order.paid", "order.cancelled", "order.shipped")
+	sub.Unsubscribe()
+
+	assert.Equal(t, []string{"audit.order.created", "audit.order.shipped"}, got.names())
+	assert.Empty(t, dispatcher.Listeners("order.created"))
+}
+
+func TestStream_Debounce(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.From(dispatcher, "cart.item_added", 8).
+		Debounce(20 * time.Millisecond).
+		Sink(got.add)
+	defer sub.Unsubscribe()
+
+	for i := 0; i < 5; i++ {
+		dispatcher.Dispatch(event.NewEvent("cart.item_added", map[string]interface{}{"item": i}))
+	}
+
+	require.Eventually(t, func() bool { return got.len() == 1 }, time.Second, time.Millisecond)
+
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, 1, got.len())
+	assert.Equal(t, 4, got.events[0].Arguments()["item"])
+}
+
+func TestStream_DebounceFlushesOnShutdown(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.From(dispatcher, "cart.item_added", 8).
+		Debounce(time.Hour).
+		Sink(got.add)
+
+	dispatchAll(dispatcher, "cart.item_added", "cart.item_added")
+	sub.Unsubscribe()
+
+	assert.Equal(t, 1, got.len())
+}
+
+func TestStream_Throttle(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.From(dispatcher, "tick", 8).
+		Throttle(time.Hour).
+		Sink(got.add)
+
+	dispatchAll(dispatcher, "tick", "tick", "tick")
+	sub.Unsubscribe()
+
+	assert.Equal(t, 1, got.len())
+}
+
+func TestStream_BufferCount(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.From(dispatcher, "cart.item_added", 8).
+		Buffer("cart.recalculate", 2, 0).
+		Sink(got.add)
+
+	dispatchAll(dispatcher, "cart.item_added", "cart.item_added", "cart.item_added")
+	sub.Unsubscribe()
+
+	require.Equal(t, []string{"cart.recalculate", "cart.recalculate"}, got.names())
+	assert.Len(t, got.events[0].(*event.Batch).Events, 2)
+	assert.Len(t, got.events[1].(*event.Batch).Events, 1)
+}
+
+func TestStream_BufferWindow(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.From(dispatcher, "cart.item_added", 8).
+		Buffer("cart.recalculate", 0, 20*time.Millisecond).
+		Sink(got.add)
+	defer sub.Unsubscribe()
+
+	dispatchAll(dispatcher, "cart.item_added", "cart.item_added", "cart.item_added")
+
+	require.Eventually(t, func() bool { return got.len() == 1 }, time.Second, time.Millisecond)
+	assert.Len(t, got.events[0].(*event.Batch).Events, 3)
+}
+
+func TestStream_Distinct(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.From(dispatcher, "status", 8).
+		Distinct(func(e event.Event) interface{} {
+			return e.Arguments()["state"]
+		}).
+		Sink(got.add)
+
+	for _, state := range []string{"up", "up", "down", "down", "up"} {
+		dispatcher.Dispatch(event.NewEvent("status", map[string]interface{}{"state": state}))
+	}
+	sub.Unsubscribe()
+
+	var states []interface{}
+	for _, e := range got.events {
+		states = append(states, e.Arguments()["state"])
+	}
+	assert.Equal(t, []interface{}{"up", "down", "up"}, states)
+}
+
+func TestStream_Merge(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.Merge(
+		event.From(dispatcher, "order.created", 8),
+		event.From(dispatcher, "user.created", 8),
+	).Sink(got.add)
+
+	dispatchAll(dispatcher, "order.created", "user.created", "user.deleted")
+	sub.Unsubscribe()
+
+	assert.ElementsMatch(t, []string{"order.created", "user.created"}, got.names())
+	assert.Empty(t, dispatcher.Listeners("order.created"))
+	assert.Empty(t, dispatcher.Listeners("user.created"))
+}
+
+func TestStream_Zip(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.Zip("pair",
+		event.From(dispatcher, "left", 8),
+		event.From(dispatcher, "right", 8),
+	).Sink(got.add)
+
+	for i := 0; i < 3; i++ {
+		dispatcher.Dispatch(event.NewEvent("left", map[string]interface{}{"n": i}))
+	}
+	for i := 0; i < 2; i++ {
+		dispatcher.Dispatch(event.NewEvent("right", map[string]interface{}{"n": i}))
+	}
+	sub.Unsubscribe()
+
+	require.Len(t, got.events, 2)
+	for i, e := range got.events {
+		batch := e.(*event.Batch)
+		assert.Equal(t, "pair", batch.Name())
+		assert.Equal(t, "left", batch.Events[0].Name())
+		assert.Equal(t, "right", batch.Events[1].Name())
+		assert.Equal(t, i, batch.Events[0].Arguments()["n"])
+		assert.Equal(t, i, batch.Events[1].Arguments()["n"])
+	}
+}
+
+func TestStream_CombineLatest(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	var got collector
+
+	sub := event.CombineLatest("quote",
+		event.From(dispatcher, "price", 8),
+		event.From(dispatcher, "rate", 8),
+	).Sink(got.add)
+
+	dispatcher.Dispatch(event.NewEvent("price", map[string]interface{}{"v": 1}))
+	time.Sleep(5 * time.Millisecond)
+	dispatcher.Dispatch(event.NewEvent("price", map[string]interface{}{"v": 2}))
+	time.Sleep(5 * time.Millisecond)
+	dispatcher.Dispatch(event.NewEvent("rate", map[string]interface{}{"v": 10}))
+	time.Sleep(5 * time.Millisecond)
+	dispatcher.Dispatch(event.NewEvent("price", map[string]interface{}{"v": 3}))
+	sub.Unsubscribe()
+
+	require.Len(t, got.events, 2)
+	values := func(e event.Event) []interface{} {
+		var v []interface{}
+		for _, combined := range e.(*event.Batch).Events {
+			v = append(v, combined.Arguments()["v"])
+		}
+		return v
+	}
+	assert.Equal(t, []interface{}{2, 10}, values(got.events[0]))
+	assert.Equal(t, []interface{}{3, 10}, values(got.events[1]))
+}
+
+func TestStream_To(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	recalculated := make(chan event.Event, 1)
+	dispatcher.AddListener("cart.recalculate", event.ListenerFunc(func(e event.Event) bool {
+		recalculated <- e
+		return true
+	}))
+
+	sub := event.From(dispatcher, "cart.item_added", 8).
+		Buffer("cart.recalculate", 3, 0).
+		To(dispatcher)
+	defer sub.Unsubscribe()
+
+	dispatchAll(dispatcher, "cart.item_added", "cart.item_added", "cart.item_added")
+
+	select {
+	case e := <-recalculated:
+		assert.Len(t, e.(*event.Batch).Events, 3)
+	case <-time.After(time.Second):
+		t.Fatal("derived event was not dispatched")
+	}
+}
+
+func TestStream_LazyUntilSink(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	// A stream that is never sunk subscribes nothing, so it cannot block dispatches
+	stream := event.From(dispatcher, "cart.item_added", 0).
+		Map(func(e event.Event) event.Event { return e }).
+		Filter(event.ArgType[string]("sku"))
+	_ = event.Merge(stream, event.From(dispatcher, "cart.item_removed", 0))
+
+	assert.Empty(t, dispatcher.Listeners("cart.item_added"))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatchAll(dispatcher, "cart.item_added", "cart.item_added", "cart.item_removed")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatch blocked on a stream that was never sunk")
+	}
+
+	c := &collector{}
+	sub := stream.Sink(c.add)
+	assert.Len(t, dispatcher.Listeners("cart.item_added"), 1)
+
+	dispatcher.Dispatch(event.NewEvent("cart.item_added", map[string]interface{}{"sku": "A-1"}))
+	sub.Unsubscribe()
+
+	assert.Equal(t, 1, c.len())
+	assert.Empty(t, dispatcher.Listeners("cart.item_added"))
+}
@@ -1,10 +1,47 @@
 package event
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"time"
 )
 
+var (
+	// ErrMethodNotFound is reported when a configured subscriber method does not exist.
+	ErrMethodNotFound = errors.New("event: subscriber method not found")
+
+	// ErrInvalidSignature is reported when a configured subscriber method has an unsupported signature.
+	ErrInvalidSignature = errors.New("event: invalid subscriber method signature")
+)
+
+// SubscriberError describes a subscriber configuration that cannot be registered.
+type SubscriberError struct {
+	// Subscriber is the subscriber being registered.
+	Subscriber interface{}
+
+	// EventName is the event the method was configured for.
+	EventName string
+
+	// Method is the configured method name.
+	Method string
+
+	// Err is ErrMethodNotFound, ErrInvalidSignature, ErrNilListener or an *OrderCycleError,
+	// possibly wrapped with details.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *SubscriberError) Error() string {
+	return fmt.Sprintf("event: cannot register %T.%s for %q: %v", e.Subscriber, e.Method, e.EventName, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *SubscriberError) Unwrap() error {
+	return e.Err
+}
+
 // SubscriberConfig represents the configuration for a subscriber method.
 type SubscriberConfig struct {
 	// Method is the name of the method to call on the subscriber.
@@ -12,6 +49,44 @@ type SubscriberConfig struct {
 
 	// Priority is the priority of the listener.
 	Priority int
+
+	// Name names the listener for the Before and After constraints of other listeners, see WithName.
+	Name string
+
+	// Before and After name the listeners this listener must run before and after, see Before and After.
+	Before []string
+	After  []string
+
+	// Filters must all match an event for the method to be called, see Where.
+	Filters []Filter
+
+	// Retry retries the method when it fails, see WithRetry. Nil means no retries.
+	Retry *RetryPolicy
+
+	// Timeout bounds the time the method may take, see WithTimeout. Zero means no bound.
+	Timeout time.Duration
+}
+
+// options returns the listener options for the configuration.
+func (c SubscriberConfig) options(subscriber Subscriber) []ListenerOption {
+	opts := []ListenerOption{
+		WithPriority(c.Priority),
+		WithName(c.Name),
+		Before(c.Before...),
+		After(c.After...),
+		Where(c.Filters...),
+		withOwner(subscriber),
+	}
+
+	if c.Retry != nil {
+		opts = append(opts, WithRetry(*c.Retry))
+	}
+
+	if c.Timeout > 0 {
+		opts = append(opts, WithTimeout(c.Timeout))
+	}
+
+	return opts
 }
 
 // Subscriber is the interface that must be implemented by event subscribers.
@@ -28,17 +103,134 @@ func (f SubscriberFunc) GetSubscribedEvents() map[string][]SubscriberConfig {
 	return f()
 }
 
+// BoundListener is a subscriber listener that has been bound ahead of time, without reflection.
+type BoundListener struct {
+	// Config is the configuration the listener is registered with.
+	Config SubscriberConfig
+
+	// Listener calls the subscriber method.
+	Listener Listener
+}
+
+// BoundSubscriber is implemented by subscribers that bind their own listeners,
+// such as the code generated by cmd/eventgen.
+//
+// RegisterSubscriber registers the listeners returned by BindListeners instead of looking up
+// the methods named by GetSubscribedEvents.
+type BoundSubscriber interface {
+	Subscriber
+
+	// BindListeners returns a map of event names to bound listeners.
+	BindListeners() map[string][]BoundListener
+}
+
 // RegisterSubscriber registers a subscriber with the dispatcher.
-func RegisterSubscriber(dispatcher Dispatcher, subscriber Subscriber) {
-	subscribedEvents := subscriber.GetSubscribedEvents()
+// The returned subscription removes every listener added by this call.
+//
+// Every configured method is looked up and checked before anything is registered. If a method
+// does not exist or has an unsupported signature, no listener is added and the returned error
+// joins one *SubscriberError per invalid configuration. If the ordering constraints of a listener
+// contradict those already registered, or the dispatcher cannot honor its options (see
+// ListenerOption), the listeners added by this call are removed again.
+func RegisterSubscriber(dispatcher Dispatcher, subscriber Subscriber) (*Subscription, error) {
+	bindings, err := bindSubscriber(subscriber)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]*Subscription, 0, len(bindings))
+	for _, b := range bindings {
+		sub, err := listen(dispatcher, b.eventName, b.Listener, b.Config.options(subscriber)...)
+		if err != nil {
+			for _, added := range subs {
+				added.Unsubscribe()
+			}
+
+			return nil, &SubscriberError{
+				Subscriber: subscriber,
+				EventName:  b.eventName,
+				Method:     b.Config.Method,
+				Err:        err,
+			}
+		}
+
+		subs = append(subs, sub)
+	}
 
-	for eventName, configs := range subscribedEvents {
+	return newSubscription(func() {
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}), nil
+}
+
+// subscriberBinding is a bound listener together with the event it is registered for.
+type subscriberBinding struct {
+	BoundListener
+	eventName string
+}
+
+// bindSubscriber returns the listeners of the subscriber, bound ahead of time for a BoundSubscriber
+// and through reflection otherwise.
+func bindSubscriber(subscriber Subscriber) ([]subscriberBinding, error) {
+	var bindings []subscriberBinding
+
+	var errs []error
+
+	if bound, ok := subscriber.(BoundSubscriber); ok {
+		for eventName, listeners := range bound.BindListeners() {
+			for _, l := range listeners {
+				if l.Listener == nil {
+					errs = append(errs, &SubscriberError{
+						Subscriber: subscriber,
+						EventName:  eventName,
+						Method:     l.Config.Method,
+						Err:        ErrNilListener,
+					})
+					continue
+				}
+
+				bindings = append(bindings, subscriberBinding{BoundListener: l, eventName: eventName})
+			}
+		}
+
+		return bindings, errors.Join(errs...)
+	}
+
+	for eventName, configs := range subscriber.GetSubscribedEvents() {
 		for _, config := range configs {
 			// Create a listener for each method
-			listener := createListenerFromSubscriber(subscriber, config.Method)
-			dispatcher.AddListener(eventName, listener, config.Priority)
+			listener, err := createListenerFromSubscriber(subscriber, config.Method)
+			if err != nil {
+				errs = append(errs, &SubscriberError{
+					Subscriber: subscriber,
+					EventName:  eventName,
+					Method:     config.Method,
+					Err:        err,
+				})
+				continue
+			}
+
+			bindings = append(bindings, subscriberBinding{
+				BoundListener: BoundListener{Config: config, Listener: listener},
+				eventName:     eventName,
+			})
 		}
 	}
+
+	return bindings, errors.Join(errs...)
+}
+
+// RemoveSubscriber removes every listener that RegisterSubscriber added for the subscriber.
+//
+// Subscribers are compared with ==, so subscribers that are not comparable, such as SubscriberFunc
+// values, are never found; use the Subscription returned by RegisterSubscriber to remove them.
+// Dispatchers other than EventDispatcher and AsyncDispatcher do not track subscribers,
+// so RemoveSubscriber has no effect on them.
+func RemoveSubscriber(dispatcher Dispatcher, subscriber Subscriber) {
+	if remover, ok := dispatcher.(ownerRemover); ok {
+		remover.removeOwner(subscriber)
+	}
 }
 
 // RegisterListener registers a method on a target object as a listener.
@@ -62,24 +254,88 @@ func RegisterListener(dispatcher Dispatcher, target interface{}, methodName stri
 	}
 }
 
-// createListenerFromSubscriber creates a listener from a subscriber object and method name.
-func createListenerFromSubscriber(subscriber interface{}, methodName string) Listener {
-	return ListenerFunc(func(event Event) bool {
-		// Get the method by name using reflection
-		method := reflect.ValueOf(subscriber).MethodByName(methodName)
-		if !method.IsValid() {
-			panic(fmt.Sprintf("Method %s does not exist on subscriber %T", methodName, subscriber))
+var (
+	// eventInterface is the reflected Event interface type.
+	eventInterface = reflect.TypeFor[Event]()
+
+	// contextInterface is the reflected context.Context interface type.
+	contextInterface = reflect.TypeFor[context.Context]()
+
+	// errorInterface is the reflected error interface type.
+	errorInterface = reflect.TypeFor[error]()
+)
+
+// createListenerFromSubscriber resolves a method of the subscriber once and binds it as a listener.
+//
+// The method takes the event, optionally preceded by a context.Context, and returns nothing,
+// a bool or an error. The event parameter may be event.Event or a concrete event type such as
+// *OrderCreatedEvent; a dispatched event of another type is reported as a *TypeMismatchError.
+func createListenerFromSubscriber(subscriber interface{}, methodName string) (Listener, error) {
+	// Get the method by name using reflection
+	method := reflect.ValueOf(subscriber).MethodByName(methodName)
+	if !method.IsValid() {
+		return nil, ErrMethodNotFound
+	}
+
+	// Call the common shapes directly
+	switch fn := method.Interface().(type) {
+	case func(Event) bool:
+		return ListenerFunc(fn), nil
+	case func(context.Context, Event) bool:
+		return ContextListenerFunc(fn), nil
+	case func(context.Context, Event) error:
+		return ErrorListenerFunc(fn), nil
+	case func(Event) error:
+		return ErrorListenerFunc(func(_ context.Context, e Event) error {
+			return fn(e)
+		}), nil
+	}
+
+	methodType := method.Type()
+	withContext := methodType.NumIn() == 2 && methodType.In(0) == contextInterface
+	if methodType.NumIn() != 1 && !withContext {
+		return nil, fmt.Errorf("%w: %s must accept an event, optionally preceded by a context.Context", ErrInvalidSignature, methodType)
+	}
+
+	eventType := methodType.In(methodType.NumIn() - 1)
+	if !eventInterface.AssignableTo(eventType) && !eventType.Implements(eventInterface) {
+		return nil, fmt.Errorf("%w: %s does not implement event.Event", ErrInvalidSignature, eventType)
+	}
+
+	if methodType.NumOut() > 1 || (methodType.NumOut() == 1 &&
+		methodType.Out(0).Kind() != reflect.Bool && methodType.Out(0) != errorInterface) {
+		return nil, fmt.Errorf("%w: %s must return nothing, a bool or an error", ErrInvalidSignature, methodType)
+	}
+
+	return ErrorListenerFunc(func(ctx context.Context, event Event) error {
+		eventValue := reflect.ValueOf(&event).Elem()
+		if !eventInterface.AssignableTo(eventType) {
+			if actual := reflect.TypeOf(event); !actual.AssignableTo(eventType) {
+				return &TypeMismatchError{EventName: event.Name(), Expected: eventType, Actual: actual}
+			}
+			eventValue = eventValue.Elem()
+		}
+
+		args := []reflect.Value{eventValue}
+		if withContext {
+			args = []reflect.Value{reflect.ValueOf(&ctx).Elem(), eventValue}
 		}
 
 		// Call the method with the event
-		results := method.Call([]reflect.Value{reflect.ValueOf(event)})
+		results := method.Call(args)
+		if len(results) == 0 {
+			return nil
+		}
 
-		// Check if the method returned a boolean
-		if len(results) > 0 && results[0].Kind() == reflect.Bool {
-			return results[0].Bool()
+		// Convert the result into the listener outcome
+		if results[0].Kind() == reflect.Bool {
+			if !results[0].Bool() {
+				return ErrListenerFailed
+			}
+			return nil
 		}
 
-		// Default to true if the method doesn't return a boolean
-		return true
-	})
+		err, _ := results[0].Interface().(error)
+		return err
+	}), nil
 }
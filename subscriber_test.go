@@ -1,10 +1,13 @@
 package event_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/parsilver/event"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type TestSubscriber struct {
@@ -49,7 +52,8 @@ func TestSubscriber_Registration(t *testing.T) {
 	subscriber := NewTestSubscriber()
 
 	// Register subscriber
-	event.RegisterSubscriber(dispatcher, subscriber)
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
 
 	// Dispatch events
 	dispatcher.Dispatch(event.NewEvent("user.created"))
@@ -86,3 +90,255 @@ func TestSubscriber_PriorityRespected(t *testing.T) {
 	// Verify call order based on priority (higher priority first)
 	assert.Equal(t, []string{"listener2", "listener1"}, callOrder)
 }
+
+type invalidSubscriber struct {
+	events map[string][]event.SubscriberConfig
+}
+
+func (s *invalidSubscriber) OnUserCreated(e event.Event) bool {
+	return true
+}
+
+func (s *invalidSubscriber) WrongArguments(name string) bool {
+	return true
+}
+
+func (s *invalidSubscriber) WrongResults(e event.Event) (bool, bool) {
+	return true, true
+}
+
+func (s *invalidSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return s.events
+}
+
+func TestSubscriber_MissingMethod(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &invalidSubscriber{events: map[string][]event.SubscriberConfig{
+		"user.created": {{Method: "OnUserCreated"}, {Method: "DoesNotExist"}},
+	}}
+
+	sub, err := event.RegisterSubscriber(dispatcher, subscriber)
+
+	assert.Nil(t, sub)
+	assert.ErrorIs(t, err, event.ErrMethodNotFound)
+
+	var subscriberErr *event.SubscriberError
+	require.True(t, errors.As(err, &subscriberErr))
+	assert.Equal(t, "DoesNotExist", subscriberErr.Method)
+	assert.Equal(t, "user.created", subscriberErr.EventName)
+
+	// Nothing is registered when any method is invalid
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("user.created"))
+	assert.Empty(t, result.Listeners)
+}
+
+func TestSubscriber_InvalidSignature(t *testing.T) {
+	for _, method := range []string{"WrongArguments", "WrongResults"} {
+		t.Run(method, func(t *testing.T) {
+			subscriber := &invalidSubscriber{events: map[string][]event.SubscriberConfig{
+				"user.created": {{Method: method}},
+			}}
+
+			_, err := event.RegisterSubscriber(event.NewDispatcher(), subscriber)
+
+			assert.ErrorIs(t, err, event.ErrInvalidSignature)
+		})
+	}
+}
+
+type looseSubscriber struct {
+	received []event.Event
+}
+
+func (s *looseSubscriber) OnAnything(e interface{}) {
+	s.received = append(s.received, e.(event.Event))
+}
+
+func (s *looseSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return map[string][]event.SubscriberConfig{
+		"user.created": {{Method: "OnAnything"}},
+	}
+}
+
+func TestSubscriber_MethodWithoutResult(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &looseSubscriber{}
+
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("user.created"))
+
+	assert.Len(t, subscriber.received, 1)
+	assert.NoError(t, result.Err())
+}
+
+type orderSubscriber struct {
+	calls []string
+	fail  error
+}
+
+func (s *orderSubscriber) OnConcrete(e *OrderCreatedEvent) {
+	s.calls = append(s.calls, "concrete:"+e.OrderID)
+}
+
+func (s *orderSubscriber) OnContext(ctx context.Context, e *OrderCreatedEvent) error {
+	s.calls = append(s.calls, "context:"+e.OrderID)
+	return s.fail
+}
+
+func (s *orderSubscriber) OnError(e event.Event) error {
+	s.calls = append(s.calls, "error:"+e.Name())
+	return nil
+}
+
+func (s *orderSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return map[string][]event.SubscriberConfig{
+		"order.created": {
+			{Method: "OnConcrete", Priority: 30},
+			{Method: "OnContext", Priority: 20},
+			{Method: "OnError", Priority: 10},
+		},
+	}
+}
+
+func TestSubscriber_FlexibleSignatures(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	errDeclined := errors.New("declined")
+	subscriber := &orderSubscriber{fail: errDeclined}
+
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), NewOrderCreatedEvent("ORD-1"))
+
+	assert.Equal(t, []string{"concrete:ORD-1", "context:ORD-1", "error:order.created"}, subscriber.calls)
+	assert.ErrorIs(t, result.Err(), errDeclined)
+	assert.Len(t, result.Failed(), 1)
+}
+
+func TestSubscriber_EventTypeMismatch(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &orderSubscriber{}
+
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	assert.Equal(t, []string{"error:order.created"}, subscriber.calls)
+
+	var mismatch *event.TypeMismatchError
+	require.True(t, errors.As(result.Err(), &mismatch))
+	assert.Equal(t, "*event.BaseEvent", mismatch.Actual.String())
+}
+
+func TestSubscriber_NonEventParameter(t *testing.T) {
+	subscriber := &invalidSubscriber{events: map[string][]event.SubscriberConfig{
+		"order.created": {{Method: "WrongArguments"}},
+	}}
+
+	_, err := event.RegisterSubscriber(event.NewDispatcher(), subscriber)
+
+	assert.ErrorIs(t, err, event.ErrInvalidSignature)
+	assert.Contains(t, err.Error(), "does not implement event.Event")
+}
+
+type boundSubscriber struct {
+	orderSubscriber
+	bindings map[string][]event.BoundListener
+}
+
+func (s *boundSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	// Not consulted for bound subscribers, so a missing method must not fail registration
+	return map[string][]event.SubscriberConfig{
+		"order.created": {{Method: "Missing"}},
+	}
+}
+
+func (s *boundSubscriber) BindListeners() map[string][]event.BoundListener {
+	return s.bindings
+}
+
+func TestSubscriber_BoundListeners(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &boundSubscriber{}
+	subscriber.bindings = map[string][]event.BoundListener{
+		"order.created": {
+			{
+				Config:   event.SubscriberConfig{Method: "OnError", Priority: 10},
+				Listener: event.ErrorListenerFunc(func(_ context.Context, e event.Event) error { return subscriber.OnError(e) }),
+			},
+			{
+				Config:   event.SubscriberConfig{Method: "OnContext", Priority: 20},
+				Listener: event.TypedContextListener(subscriber.OnContext),
+			},
+		},
+	}
+
+	sub, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(NewOrderCreatedEvent("ORD-1"))
+	assert.Equal(t, []string{"context:ORD-1", "error:order.created"}, subscriber.calls)
+
+	sub.Unsubscribe()
+	dispatcher.Dispatch(NewOrderCreatedEvent("ORD-2"))
+	assert.Len(t, subscriber.calls, 2)
+}
+
+func TestSubscriber_NilBoundListener(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &boundSubscriber{bindings: map[string][]event.BoundListener{
+		"order.created": {{Config: event.SubscriberConfig{Method: "OnConcrete"}}},
+	}}
+
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+
+	var subErr *event.SubscriberError
+	require.True(t, errors.As(err, &subErr))
+	assert.Equal(t, "OnConcrete", subErr.Method)
+	assert.ErrorIs(t, err, event.ErrNilListener)
+}
+
+// plainDispatcher is a Dispatcher that only implements the Dispatcher interface.
+type plainDispatcher struct {
+	dispatcher *event.EventDispatcher
+}
+
+func (d plainDispatcher) AddListener(eventName string, listener event.Listener, priority ...int) {
+	d.dispatcher.AddListener(eventName, listener, priority...)
+}
+
+func (d plainDispatcher) HasListener(eventName string, listener event.Listener) bool {
+	return d.dispatcher.HasListener(eventName, listener)
+}
+
+func (d plainDispatcher) RemoveListener(eventName string, listener event.Listener) {
+	d.dispatcher.RemoveListener(eventName, listener)
+}
+
+func (d plainDispatcher) Dispatch(e event.Event) event.Event {
+	return d.dispatcher.Dispatch(e)
+}
+
+func TestSubscriber_UnsupportedOption(t *testing.T) {
+	dispatcher := plainDispatcher{dispatcher: event.NewDispatcher()}
+
+	_, err := event.RegisterSubscriber(dispatcher, &tenantSubscriber{})
+
+	var subErr *event.SubscriberError
+	require.ErrorAs(t, err, &subErr)
+	assert.Equal(t, "OnOrderCreated", subErr.Method)
+	assert.ErrorIs(t, err, event.ErrUnsupportedOption)
+	assert.Contains(t, err.Error(), "Where")
+	assert.Empty(t, dispatcher.dispatcher.Listeners("order.created"))
+
+	// A priority is all such a dispatcher needs to honor
+	subscriber := NewTestSubscriber()
+	_, err = event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	assert.True(t, subscriber.calledEvents["user.created"])
+}
@@ -0,0 +1,228 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNilListener is returned when a nil listener is registered.
+var ErrNilListener = errors.New("event: listener is nil")
+
+// ErrUnsupportedOption is returned when a listener is registered with options its dispatcher cannot honor.
+var ErrUnsupportedOption = errors.New("event: listener option not supported by this dispatcher")
+
+// ListenerID identifies a single listener registration on an EventDispatcher.
+type ListenerID uint64
+
+// Subscription is a handle to one or more listener registrations.
+//
+// Unlike RemoveListener, it does not rely on comparing listeners, so it also removes
+// ListenerFunc closures and the listeners created by RegisterSubscriber.
+type Subscription struct {
+	once        sync.Once
+	unsubscribe func()
+}
+
+// newSubscription creates a subscription that calls unsubscribe once.
+func newSubscription(unsubscribe func()) *Subscription {
+	return &Subscription{unsubscribe: unsubscribe}
+}
+
+// Unsubscribe removes the listeners registered by this subscription.
+// Calling it more than once has no further effect.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(s.unsubscribe)
+}
+
+// listenerOptions holds the settings applied by ListenerOption values.
+type listenerOptions struct {
+	priority int
+	owner    interface{}
+	name     string
+	before   []string
+	after    []string
+	times    int64
+	until    time.Time
+	filters  []Filter
+	retry    *RetryPolicy
+	timeout  time.Duration
+}
+
+// ListenerOption configures a listener registered with Listen.
+//
+// EventDispatcher and AsyncDispatcher support every option. Other Dispatcher implementations only
+// support WithPriority, and RegisterSubscriber returns ErrUnsupportedOption, registering nothing,
+// when a subscriber asks such a dispatcher for more.
+type ListenerOption func(*listenerOptions)
+
+// WithPriority sets the priority of the listener. Higher values mean earlier execution.
+func WithPriority(priority int) ListenerOption {
+	return func(o *listenerOptions) {
+		o.priority = priority
+	}
+}
+
+// withOwner records the subscriber that registered the listener.
+func withOwner(owner interface{}) ListenerOption {
+	return func(o *listenerOptions) {
+		o.owner = owner
+	}
+}
+
+// Listen adds a listener for the specified event and returns a subscription to remove it.
+func (d *EventDispatcher) Listen(eventName string, listener Listener, opts ...ListenerOption) (*Subscription, error) {
+	if listener == nil {
+		return nil, ErrNilListener
+	}
+
+	var options listenerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	id, err := d.add(eventName, listener, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSubscription(func() {
+		d.remove(eventName, id)
+	}), nil
+}
+
+// remove removes the listener registration with the given ID from the specified event.
+func (d *EventDispatcher) remove(eventName string, id ListenerID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.removeWhere(eventName, func(registered ListenerPriority) bool {
+		return registered.id == id
+	})
+}
+
+// removeOwner removes every listener registered by the given owner.
+func (d *EventDispatcher) removeOwner(owner interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	isOwned := func(registered ListenerPriority) bool {
+		return registered.owner != nil && sameValue(registered.owner, owner)
+	}
+
+	reg := d.registry.Load()
+	for eventName := range reg.listeners {
+		d.removeWhere(eventName, isOwned)
+	}
+
+	for eventType := range reg.typed {
+		d.updateTyped(eventType, func(listeners EventListeners) EventListeners {
+			kept := make(EventListeners, 0, len(listeners))
+			for _, registered := range listeners {
+				if !isOwned(registered) {
+					kept = append(kept, registered)
+				}
+			}
+
+			return kept
+		})
+	}
+}
+
+// listenRegistrar is implemented by dispatchers that return subscriptions for their listeners.
+type listenRegistrar interface {
+	Listen(eventName string, listener Listener, opts ...ListenerOption) (*Subscription, error)
+}
+
+// ownerRemover is implemented by dispatchers that can remove listeners by the subscriber that registered them.
+type ownerRemover interface {
+	removeOwner(owner interface{})
+}
+
+// unsupported returns the names of the options that set more than the priority.
+func (o listenerOptions) unsupported() []string {
+	var names []string
+	if o.name != "" {
+		names = append(names, "WithName")
+	}
+	if len(o.before) > 0 {
+		names = append(names, "Before")
+	}
+	if len(o.after) > 0 {
+		names = append(names, "After")
+	}
+	if o.times > 0 {
+		names = append(names, "Times")
+	}
+	if !o.until.IsZero() {
+		names = append(names, "Until")
+	}
+	if len(o.filters) > 0 {
+		names = append(names, "Where")
+	}
+	if o.retry != nil {
+		names = append(names, "WithRetry")
+	}
+	if o.timeout > 0 {
+		names = append(names, "WithTimeout")
+	}
+
+	return names
+}
+
+// listen registers the listener on any Dispatcher and returns a subscription for it.
+// Dispatchers without Listen fall back to AddListener and RemoveListener, which only take a
+// priority, so any other option is rejected with ErrUnsupportedOption.
+func listen(d Dispatcher, eventName string, listener Listener, opts ...ListenerOption) (*Subscription, error) {
+	if registrar, ok := d.(listenRegistrar); ok {
+		return registrar.Listen(eventName, listener, opts...)
+	}
+
+	var options listenerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if listener == nil {
+		return nil, ErrNilListener
+	}
+
+	if names := options.unsupported(); len(names) > 0 {
+		return nil, fmt.Errorf("%w: %s on %T", ErrUnsupportedOption, strings.Join(names, ", "), d)
+	}
+
+	d.AddListener(eventName, listener, options.priority)
+
+	return newSubscription(func() {
+		d.RemoveListener(eventName, listener)
+	}), nil
+}
+
+// removeID returns the listeners without the one with the given ID.
+func removeID(listeners EventListeners, id ListenerID) EventListeners {
+	kept := make(EventListeners, 0, len(listeners))
+	for _, registered := range listeners {
+		if registered.id != id {
+			kept = append(kept, registered)
+		}
+	}
+
+	return kept
+}
+
+// sameValue reports whether a and b are equal, treating values that cannot be compared
+// with == as different instead of panicking.
+func sameValue(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.ValueOf(a).Comparable() {
+		return false
+	}
+
+	return a == b
+}
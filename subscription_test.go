@@ -0,0 +1,146 @@
+package event_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen_UnsubscribeListenerFunc(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	sub, err := dispatcher.Listen("user.created", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}), event.WithPriority(10))
+	require.NoError(t, err)
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestListen_UnsubscribeOnlyRemovesOwnRegistration(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	listener := &TestListener{}
+
+	first, err := dispatcher.Listen("user.created", listener)
+	require.NoError(t, err)
+	_, err = dispatcher.Listen("user.created", listener)
+	require.NoError(t, err)
+
+	first.Unsubscribe()
+
+	assert.True(t, dispatcher.HasListener("user.created", listener))
+}
+
+func TestListen_NilListener(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	_, err := dispatcher.Listen("user.created", nil)
+
+	assert.ErrorIs(t, err, event.ErrNilListener)
+}
+
+func TestListen_Pattern(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	listener := &TestListener{}
+
+	sub, err := dispatcher.Listen("user.*", listener)
+	require.NoError(t, err)
+	sub.Unsubscribe()
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.False(t, listener.called)
+}
+
+type uncomparableListener struct {
+	handle func(event.Event) bool
+}
+
+func (l uncomparableListener) Handle(e event.Event) bool {
+	return l.handle(e)
+}
+
+func TestDispatcher_UncomparableListeners(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	listener := uncomparableListener{handle: func(event.Event) bool { return true }}
+	fn := event.ListenerFunc(func(event.Event) bool { return true })
+
+	dispatcher.AddListener("user.created", listener)
+	dispatcher.AddListener("user.created", fn)
+
+	assert.NotPanics(t, func() {
+		assert.False(t, dispatcher.HasListener("user.created", listener))
+		assert.False(t, dispatcher.HasListener("user.created", fn))
+		dispatcher.RemoveListener("user.created", listener)
+		dispatcher.RemoveListener("user.created", fn)
+	})
+}
+
+func TestOn_Unsubscribe(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	calls := 0
+	sub := event.On(dispatcher, func(e *OrderCreatedEvent) error {
+		calls++
+		return nil
+	})
+
+	dispatcher.Dispatch(NewOrderCreatedEvent("ORD-1"))
+	sub.Unsubscribe()
+	dispatcher.Dispatch(NewOrderCreatedEvent("ORD-2"))
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestRegisterSubscriber_Unsubscribe(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := NewTestSubscriber()
+
+	sub, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+	sub.Unsubscribe()
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+	dispatcher.Dispatch(event.NewEvent("user.updated"))
+
+	assert.Empty(t, subscriber.calledEvents)
+}
+
+func TestRemoveSubscriber(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := NewTestSubscriber()
+	other := NewTestSubscriber()
+
+	event.RegisterSubscriber(dispatcher, subscriber)
+	event.RegisterSubscriber(dispatcher, other)
+	event.RemoveSubscriber(dispatcher, subscriber)
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.Empty(t, subscriber.calledEvents)
+	assert.True(t, other.calledEvents["user.created"])
+}
+
+func TestRemoveSubscriber_AsyncDispatcher(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	async := event.NewAsyncDispatcher(dispatcher)
+	defer async.Shutdown(context.Background())
+	subscriber := NewTestSubscriber()
+
+	event.RegisterSubscriber(async, subscriber)
+	event.RemoveSubscriber(async, subscriber)
+
+	dispatcher.Dispatch(event.NewEvent("user.created"))
+
+	assert.Empty(t, subscriber.calledEvents)
+}
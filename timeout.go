@@ -0,0 +1,190 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrListenerTimeout is recorded for listeners that exceeded their time budget.
+var ErrListenerTimeout = errors.New("event: listener timed out")
+
+// TimeoutPolicy controls what the dispatcher does after a listener timed out.
+type TimeoutPolicy int
+
+const (
+	// TimeoutStop skips the remaining listeners and returns the listener's error from
+	// DispatchContext and Future.Err. This is the default.
+	TimeoutStop TimeoutPolicy = iota
+
+	// TimeoutContinue continues with the next listener.
+	TimeoutContinue
+)
+
+// WithTimeoutPolicy sets what the dispatcher does after a listener timed out.
+func WithTimeoutPolicy(policy TimeoutPolicy) DispatcherOption {
+	return func(d *EventDispatcher) {
+		d.timeoutPolicy = policy
+	}
+}
+
+// WithDispatchTimeout bounds the time a dispatch may take. Once it has passed, the listener running
+// at that moment times out and the remaining listeners are not called, as if the context was done.
+func WithDispatchTimeout(timeout time.Duration) DispatcherOption {
+	return func(d *EventDispatcher) {
+		d.dispatchTimeout = timeout
+	}
+}
+
+// WithTimeout bounds the time the listener may take to handle an event.
+//
+// Once the budget has passed, the context given to the listener is canceled, the dispatch stops
+// waiting for it and records OutcomeTimedOut, and the dispatcher continues according to its
+// TimeoutPolicy. A listener that ignores its context keeps running in the background, so it must
+// be safe to run alongside the listeners after it. Calls that timed out are not retried, but they
+// are dead-lettered.
+func WithTimeout(timeout time.Duration) ListenerOption {
+	return func(o *listenerOptions) {
+		o.timeout = timeout
+	}
+}
+
+// SlowListener describes a listener that took longer than the threshold set with WithSlowListenerHandler.
+type SlowListener struct {
+	// EventName is the name of the event the listener handled.
+	EventName string
+
+	// Listener describes the listener.
+	Listener ListenerInfo
+
+	// Duration is how long the listener took.
+	Duration time.Duration
+}
+
+// WithSlowListenerHandler calls the handler for every listener call that takes longer than the threshold.
+// The handler is called once the listener has returned, even when the dispatch stopped waiting for it
+// because it timed out.
+func WithSlowListenerHandler(threshold time.Duration, handler func(SlowListener)) DispatcherOption {
+	return func(d *EventDispatcher) {
+		d.slowThreshold = threshold
+		d.slowHandler = handler
+	}
+}
+
+// withDispatchTimeout applies the dispatch timeout of the dispatcher to the context.
+func (d *EventDispatcher) withDispatchTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.dispatchTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, d.dispatchTimeout)
+}
+
+// callListener calls the listener within its time budget and reports it if it was slow. The budget
+// is the listener's timeout and the deadline of the context, which includes the dispatch timeout.
+func (d *EventDispatcher) callListener(ctx context.Context, l ListenerPriority, event Event) error {
+	_, hasDeadline := ctx.Deadline()
+	if l.timeout <= 0 && !hasDeadline && d.slowHandler == nil {
+		return d.call(ctx, l.Listener, event)
+	}
+
+	if l.timeout <= 0 && !hasDeadline {
+		start := time.Now()
+		err := d.call(ctx, l.Listener, event)
+		d.reportSlow(event, l, time.Since(start))
+		return err
+	}
+
+	return d.callWithTimeout(ctx, l, event)
+}
+
+// callWithTimeout calls the listener on its own goroutine and stops waiting for it once its
+// time budget has passed.
+func (d *EventDispatcher) callWithTimeout(ctx context.Context, l ListenerPriority, event Event) error {
+	start := time.Now()
+	callCtx, cancel := ctx, context.CancelFunc(func() {})
+	if l.timeout > 0 {
+		callCtx, cancel = context.WithTimeout(ctx, l.timeout)
+	}
+
+	// abandoned records, under mu, that the dispatch stopped waiting for the listener
+	var mu sync.Mutex
+	abandoned := false
+
+	done := make(chan error, 1)
+	go func() {
+		defer cancel()
+
+		err := d.call(callCtx, l.Listener, event)
+		d.reportSlow(event, l, time.Since(start))
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !abandoned {
+			done <- err
+			return
+		}
+
+		// Nobody can re-raise a panic once the dispatch has moved on, so it is reported instead
+		var panicErr *ListenerPanicError
+		if errors.As(err, &panicErr) && d.recovery == PanicPropagate && d.panicHandler != nil {
+			d.panicHandler(panicErr)
+		}
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-callCtx.Done():
+	}
+
+	// A listener whose context was canceled rather than timed out is waited for as usual
+	cause := context.Cause(callCtx)
+	if !errors.Is(cause, context.DeadlineExceeded) {
+		return <-done
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	select {
+	case err := <-done:
+		// The listener returned right at the deadline
+		return err
+	default:
+		abandoned = true
+	}
+
+	return fmt.Errorf("%w: %w", ErrListenerTimeout, cause)
+}
+
+// stoppedBy returns the error that stopped the dispatch recorded in the result: the cause of the
+// context's cancellation, or the error of a listener that timed out under TimeoutStop.
+func (d *EventDispatcher) stoppedBy(result *DispatchResult) error {
+	if result.ContextErr != nil || d.timeoutPolicy != TimeoutStop {
+		return result.ContextErr
+	}
+
+	for _, l := range result.Listeners {
+		if l.Outcome == OutcomeTimedOut {
+			return l.Err
+		}
+	}
+
+	return nil
+}
+
+// timedOut reports whether the error is the error of a listener that timed out.
+func timedOut(err error) bool {
+	return errors.Is(err, ErrListenerTimeout)
+}
+
+// reportSlow calls the slow-listener handler if the call took longer than the threshold.
+func (d *EventDispatcher) reportSlow(event Event, l ListenerPriority, duration time.Duration) {
+	if d.slowHandler != nil && duration > d.slowThreshold {
+		d.slowHandler(SlowListener{EventName: event.Name(), Listener: l.info(), Duration: duration})
+	}
+}
@@ -0,0 +1,346 @@
+package event_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stuck returns a listener that ignores its context and blocks until release is closed.
+func stuck(release <-chan struct{}) event.Listener {
+	return event.ListenerFunc(func(e event.Event) bool {
+		<-release
+		return true
+	})
+}
+
+func TestTimeout_ListenerTimesOut(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := dispatcher.Listen("order.created", stuck(release), event.WithTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	calls := 0
+	dispatcher.AddListener("order.created", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}))
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	assert.ErrorIs(t, result.Err(), event.ErrListenerTimeout)
+	assert.ErrorIs(t, result.Err(), context.DeadlineExceeded)
+	assert.Equal(t, event.OutcomeTimedOut, result.Listeners[0].Outcome)
+	assert.True(t, result.Listeners[0].Ran())
+	assert.Len(t, result.Failed(), 1)
+	assert.Equal(t, event.OutcomeSkipped, result.Listeners[1].Outcome)
+	assert.Zero(t, calls)
+}
+
+func TestTimeout_CancelsListenerContext(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var cause error
+	_, err := dispatcher.Listen("order.created", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		<-ctx.Done()
+		cause = context.Cause(ctx)
+		return cause
+	}), event.WithTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	// The listener either returned at the deadline or was abandoned; it failed either way
+	assert.ErrorIs(t, result.Err(), context.DeadlineExceeded)
+	assert.Contains(t, []event.Outcome{event.OutcomeFailed, event.OutcomeTimedOut}, result.Listeners[0].Outcome)
+}
+
+func TestTimeout_Continue(t *testing.T) {
+	dispatcher := event.NewDispatcher(event.WithTimeoutPolicy(event.TimeoutContinue))
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := dispatcher.Listen("order.created", stuck(release), event.WithTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	calls := 0
+	dispatcher.AddListener("order.created", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}))
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+	assert.Equal(t, 1, calls)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+	assert.Equal(t, event.OutcomeTimedOut, result.Listeners[0].Outcome)
+	assert.Equal(t, event.OutcomeHandled, result.Listeners[1].Outcome)
+	assert.Equal(t, 2, calls)
+}
+
+func TestTimeout_Stop(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := dispatcher.Listen("order.created", stuck(release), event.WithTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	calls := 0
+	dispatcher.AddListener("order.created", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}))
+
+	_, err = dispatcher.DispatchContext(context.Background(), event.NewEvent("order.created"))
+
+	var listenerErr *event.ListenerError
+	require.ErrorAs(t, err, &listenerErr)
+	assert.ErrorIs(t, err, event.ErrListenerTimeout)
+	assert.Zero(t, calls)
+
+	// Middleware does not change the outcome
+	dispatcher.UseListener(func(next event.Handler) event.Handler {
+		return next
+	})
+
+	_, err = dispatcher.DispatchContext(context.Background(), event.NewEvent("order.created"))
+	assert.ErrorIs(t, err, event.ErrListenerTimeout)
+	assert.Zero(t, calls)
+}
+
+func TestTimeout_StopAsync(t *testing.T) {
+	dispatcher := event.NewAsyncDispatcher(nil)
+	defer dispatcher.Shutdown(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := dispatcher.Listen("order.created", stuck(release), event.WithTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	future, err := dispatcher.DispatchAsync(event.NewEvent("order.created"))
+	require.NoError(t, err)
+
+	_, err = future.Wait(context.Background())
+	assert.ErrorIs(t, err, event.ErrListenerTimeout)
+}
+
+func TestTimeout_PanicAfterTimeout(t *testing.T) {
+	reported := make(chan *event.ListenerPanicError, 1)
+	dispatcher := event.NewDispatcher(event.WithPanicHandler(func(err *event.ListenerPanicError) {
+		reported <- err
+	}))
+	release := make(chan struct{})
+
+	_, err := dispatcher.Listen("order.created", event.ListenerFunc(func(e event.Event) bool {
+		<-release
+		panic("too late")
+	}), event.WithTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = dispatcher.DispatchContext(context.Background(), event.NewEvent("order.created"))
+	assert.ErrorIs(t, err, event.ErrListenerTimeout)
+
+	close(release)
+
+	select {
+	case err := <-reported:
+		assert.Equal(t, "too late", err.Value)
+	case <-time.After(time.Second):
+		t.Fatal("panic was not reported")
+	}
+}
+
+func TestTimeout_FastListener(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	_, err := dispatcher.Listen("order.created", event.ListenerFunc(func(e event.Event) bool {
+		return true
+	}), event.WithTimeout(time.Second))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	require.NoError(t, result.Err())
+	assert.Equal(t, event.OutcomeHandled, result.Listeners[0].Outcome)
+}
+
+func TestTimeout_DispatchTimeout(t *testing.T) {
+	dispatcher := event.NewDispatcher(event.WithDispatchTimeout(20 * time.Millisecond))
+	release := make(chan struct{})
+	defer close(release)
+
+	calls := 0
+	dispatcher.AddListener("order.created", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}), 10)
+	dispatcher.AddListener("order.created", stuck(release), 5)
+	dispatcher.AddListener("order.created", event.ListenerFunc(func(e event.Event) bool {
+		calls++
+		return true
+	}))
+
+	_, err := dispatcher.DispatchContext(context.Background(), event.NewEvent("order.created"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, calls)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+	assert.Equal(t, event.OutcomeHandled, result.Listeners[0].Outcome)
+	assert.Equal(t, event.OutcomeTimedOut, result.Listeners[1].Outcome)
+	assert.Equal(t, event.OutcomeCanceled, result.Listeners[2].Outcome)
+	assert.ErrorIs(t, result.ContextErr, context.DeadlineExceeded)
+	assert.Equal(t, 2, calls)
+}
+
+func TestTimeout_ContextDeadline(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	release := make(chan struct{})
+	defer close(release)
+
+	dispatcher.AddListener("order.created", stuck(release))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	done := make(chan *event.DispatchResult, 1)
+	go func() {
+		done <- dispatcher.DispatchWithResult(ctx, event.NewEvent("order.created"))
+	}()
+
+	select {
+	case result := <-done:
+		assert.Equal(t, event.OutcomeTimedOut, result.Listeners[0].Outcome)
+		assert.ErrorIs(t, result.Err(), context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("dispatch did not stop at the deadline of its context")
+	}
+}
+
+func TestTimeout_CanceledContextIsNotATimeout(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := dispatcher.Listen("order.created", event.ErrorListenerFunc(func(ctx context.Context, e event.Event) error {
+		cancel()
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}), event.WithTimeout(time.Second))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(ctx, event.NewEvent("order.created"))
+
+	assert.Equal(t, event.OutcomeHandled, result.Listeners[0].Outcome)
+	assert.NotErrorIs(t, result.Err(), event.ErrListenerTimeout)
+}
+
+func TestTimeout_DeadLetter(t *testing.T) {
+	sink := event.NewMemoryDeadLetterSink()
+	dispatcher := event.NewDispatcher(event.WithDeadLetters(sink))
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := dispatcher.Listen("order.created", stuck(release),
+		event.WithTimeout(10*time.Millisecond),
+		event.WithRetry(event.RetryPolicy{MaxAttempts: 3}))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+	assert.Equal(t, 1, result.Listeners[0].Attempts)
+
+	letters, err := sink.List()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.ErrorIs(t, letters[0].Attempts[0].Err, event.ErrListenerTimeout)
+}
+
+func TestTimeout_SlowListener(t *testing.T) {
+	var mu sync.Mutex
+	var slow []event.SlowListener
+
+	dispatcher := event.NewDispatcher(event.WithSlowListenerHandler(5*time.Millisecond, func(s event.SlowListener) {
+		mu.Lock()
+		defer mu.Unlock()
+		slow = append(slow, s)
+	}))
+
+	_, err := dispatcher.Listen("order.created", event.ListenerFunc(func(e event.Event) bool {
+		time.Sleep(10 * time.Millisecond)
+		return true
+	}), event.WithName("billing"))
+	require.NoError(t, err)
+	dispatcher.AddListener("order.created", event.ListenerFunc(func(e event.Event) bool {
+		return true
+	}))
+
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, slow, 1)
+	assert.Equal(t, "order.created", slow[0].EventName)
+	assert.Equal(t, "billing", slow[0].Listener.Name)
+	assert.GreaterOrEqual(t, slow[0].Duration, 10*time.Millisecond)
+}
+
+func TestTimeout_SlowListenerAfterTimeout(t *testing.T) {
+	reported := make(chan event.SlowListener, 1)
+	dispatcher := event.NewDispatcher(event.WithSlowListenerHandler(5*time.Millisecond, func(s event.SlowListener) {
+		reported <- s
+	}))
+	release := make(chan struct{})
+
+	_, err := dispatcher.Listen("order.created", stuck(release), event.WithTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+	assert.Equal(t, event.OutcomeTimedOut, result.Listeners[0].Outcome)
+
+	// The listener is reported once it finally returns
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	select {
+	case s := <-reported:
+		assert.GreaterOrEqual(t, s.Duration, 20*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Fatal("slow listener was not reported")
+	}
+}
+
+type timingSubscriber struct {
+	release chan struct{}
+}
+
+func (s *timingSubscriber) OnOrderCreated(e event.Event) bool {
+	<-s.release
+	return true
+}
+
+func (s *timingSubscriber) GetSubscribedEvents() map[string][]event.SubscriberConfig {
+	return map[string][]event.SubscriberConfig{
+		"order.created": {
+			{Method: "OnOrderCreated", Timeout: 10 * time.Millisecond},
+		},
+	}
+}
+
+func TestTimeout_SubscriberConfig(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	subscriber := &timingSubscriber{release: make(chan struct{})}
+	defer close(subscriber.release)
+
+	_, err := event.RegisterSubscriber(dispatcher, subscriber)
+	require.NoError(t, err)
+
+	result := dispatcher.DispatchWithResult(context.Background(), event.NewEvent("order.created"))
+
+	assert.Equal(t, event.OutcomeTimedOut, result.Listeners[0].Outcome)
+}
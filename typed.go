@@ -0,0 +1,140 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// TypeMismatchError is returned by typed listeners when the dispatched event is not of the expected type.
+type TypeMismatchError struct {
+	// EventName is the name of the dispatched event.
+	EventName string
+
+	// Expected is the type the listener accepts.
+	Expected reflect.Type
+
+	// Actual is the dynamic type of the dispatched event.
+	Actual reflect.Type
+}
+
+// Error implements the error interface.
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("event: listener for %q expects %v, got %v", e.EventName, e.Expected, e.Actual)
+}
+
+// typedListener adapts a function taking a concrete event type to the ErrorListener interface.
+type typedListener[T Event] struct {
+	fn func(context.Context, T) error
+}
+
+// Handle implements the Listener interface for typedListener.
+func (l typedListener[T]) Handle(e Event) bool {
+	return l.HandleEvent(context.Background(), e) == nil
+}
+
+// HandleEvent implements the ErrorListener interface for typedListener.
+func (l typedListener[T]) HandleEvent(ctx context.Context, e Event) error {
+	typed, ok := e.(T)
+	if !ok {
+		return &TypeMismatchError{
+			EventName: e.Name(),
+			Expected:  reflect.TypeFor[T](),
+			Actual:    reflect.TypeOf(e),
+		}
+	}
+
+	return l.fn(ctx, typed)
+}
+
+// TypedListener returns a listener that passes events to fn as T.
+// Events of another type are reported as a *TypeMismatchError instead of being skipped.
+func TypedListener[T Event](fn func(T) error) Listener {
+	return typedListener[T]{fn: func(_ context.Context, e T) error {
+		return fn(e)
+	}}
+}
+
+// TypedContextListener is like TypedListener for functions that also take the dispatch context.
+func TypedContextListener[T Event](fn func(context.Context, T) error) Listener {
+	return typedListener[T]{fn: fn}
+}
+
+// On registers fn for every dispatched event whose dynamic type is T, whatever its name.
+// If T is an interface type, fn receives every event implementing it.
+// The returned subscription removes the listener again.
+//
+//	event.On(dispatcher, func(e *OrderCreatedEvent) error {
+//		return ship(e.OrderID)
+//	})
+func On[T Event](d *EventDispatcher, fn func(T) error, priority ...int) *Subscription {
+	var opts listenerOptions
+	if len(priority) > 0 {
+		opts.priority = priority[0]
+	}
+
+	eventType := reflect.TypeFor[T]()
+	id := d.addTypeListener(eventType, TypedListener(fn), opts)
+
+	return newSubscription(func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		d.updateTyped(eventType, func(listeners EventListeners) EventListeners {
+			return removeID(listeners, id)
+		})
+	})
+}
+
+// OnEvent registers fn for the named event, passing the event as T.
+// Dispatching an event of another type under that name is reported as a *TypeMismatchError.
+// The returned subscription removes the listener again.
+func OnEvent[T Event](d Dispatcher, eventName string, fn func(T) error, priority ...int) *Subscription {
+	var opts []ListenerOption
+	if len(priority) > 0 {
+		opts = append(opts, WithPriority(priority[0]))
+	}
+
+	// A typed listener is never nil, so registration cannot fail
+	sub, _ := listen(d, eventName, TypedListener(fn), opts...)
+	return sub
+}
+
+// Emit dispatches the event within the given context and returns it as its concrete type,
+// together with the errors of the listeners that failed.
+func Emit[T Event](ctx context.Context, d *EventDispatcher, e T) (T, error) {
+	result := d.DispatchWithResult(ctx, e)
+	return e, result.Err()
+}
+
+// addTypeListener adds a listener for events of the given dynamic type and returns its ID.
+func (d *EventDispatcher) addTypeListener(eventType reflect.Type, listener Listener, opts listenerOptions) ListenerID {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.lastID++
+	l := ListenerPriority{
+		Listener: listener,
+		Priority: opts.priority,
+		id:       d.lastID,
+		owner:    opts.owner,
+	}
+
+	d.updateTyped(eventType, func(listeners EventListeners) EventListeners {
+		// Type listeners have no ordering constraints
+		ordered, _ := orderListeners(append(slices.Clip(listeners), l))
+		return ordered
+	})
+
+	return d.lastID
+}
+
+// matchesType reports whether listeners registered for the given type receive events of the dynamic type.
+func matchesType(registered, dynamic reflect.Type) bool {
+	if registered.Kind() == reflect.Interface {
+		return dynamic.Implements(registered)
+	}
+
+	return registered == dynamic
+}
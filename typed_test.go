@@ -0,0 +1,143 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/parsilver/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type OrderCreatedEvent struct {
+	*event.BaseEvent
+	OrderID string
+}
+
+func NewOrderCreatedEvent(orderID string) *OrderCreatedEvent {
+	return &OrderCreatedEvent{
+		BaseEvent: event.NewEvent("order.created"),
+		OrderID:   orderID,
+	}
+}
+
+type Shippable interface {
+	event.Event
+	ShippingAddress() string
+}
+
+type OrderShippedEvent struct {
+	*event.BaseEvent
+}
+
+func (e *OrderShippedEvent) ShippingAddress() string {
+	return "221B Baker Street"
+}
+
+func TestOn_RoutesByType(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var received []string
+	event.On(dispatcher, func(e *OrderCreatedEvent) error {
+		received = append(received, e.OrderID)
+		return nil
+	})
+
+	dispatcher.Dispatch(NewOrderCreatedEvent("ORD-1"))
+	dispatcher.Dispatch(event.NewEvent("order.created"))
+
+	assert.Equal(t, []string{"ORD-1"}, received)
+}
+
+func TestOn_InterfaceType(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var address string
+	event.On(dispatcher, func(e Shippable) error {
+		address = e.ShippingAddress()
+		return nil
+	})
+
+	dispatcher.Dispatch(&OrderShippedEvent{BaseEvent: event.NewEvent("order.shipped")})
+
+	assert.Equal(t, "221B Baker Street", address)
+}
+
+func TestOn_MergesWithNamedListeners(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	var calls []string
+	dispatcher.AddListener("order.created", event.ListenerFunc(func(e event.Event) bool {
+		calls = append(calls, "named")
+		return true
+	}), 10)
+
+	event.On(dispatcher, func(e *OrderCreatedEvent) error {
+		calls = append(calls, "typed")
+		return nil
+	}, 20)
+
+	dispatcher.Dispatch(NewOrderCreatedEvent("ORD-1"))
+
+	assert.Equal(t, []string{"typed", "named"}, calls)
+}
+
+func TestOnEvent_ReportsMismatch(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+
+	called := false
+	event.OnEvent(dispatcher, "order.created", func(e *OrderCreatedEvent) error {
+		called = true
+		return nil
+	})
+
+	_, err := event.Emit(context.Background(), dispatcher, event.NewEvent("order.created"))
+
+	assert.False(t, called)
+
+	var mismatch *event.TypeMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, "order.created", mismatch.EventName)
+	assert.Equal(t, "*event_test.OrderCreatedEvent", mismatch.Expected.String())
+	assert.Equal(t, "*event.BaseEvent", mismatch.Actual.String())
+}
+
+func TestEmit_ReturnsConcreteType(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	errOutOfStock := errors.New("out of stock")
+
+	event.OnEvent(dispatcher, "order.created", func(e *OrderCreatedEvent) error {
+		return errOutOfStock
+	})
+
+	order, err := event.Emit(context.Background(), dispatcher, NewOrderCreatedEvent("ORD-2"))
+
+	assert.Equal(t, "ORD-2", order.OrderID)
+	assert.ErrorIs(t, err, errOutOfStock)
+}
+
+func TestTypedListener_Handle(t *testing.T) {
+	listener := event.TypedListener(func(e *OrderCreatedEvent) error {
+		return nil
+	})
+
+	assert.True(t, listener.Handle(NewOrderCreatedEvent("ORD-3")))
+	assert.False(t, listener.Handle(event.NewEvent("order.created")))
+}
+
+func TestTypedContextListener_ReceivesContext(t *testing.T) {
+	dispatcher := event.NewDispatcher()
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	var got interface{}
+	dispatcher.AddListener("order.created", event.TypedContextListener(func(ctx context.Context, e *OrderCreatedEvent) error {
+		got = ctx.Value(ctxKey{})
+		return nil
+	}))
+
+	_, err := dispatcher.DispatchContext(ctx, NewOrderCreatedEvent("ORD-4"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "value", got)
+}